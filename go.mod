@@ -1,11 +1,13 @@
 module kook-go-sdk
 
-go 1.21
+go 1.22
 
 require (
 	github.com/gorilla/websocket v1.5.1
+	github.com/klauspost/compress v1.18.0
 	github.com/sirupsen/logrus v1.9.3
 	github.com/stretchr/testify v1.8.4
+	go.uber.org/goleak v1.3.0
 )
 
 require (
@@ -1,15 +1,19 @@
 package kook
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // AssetService 媒体资源相关API服务
@@ -30,98 +34,148 @@ func (s *AssetService) UploadFile(ctx context.Context, filePath string) (*Asset,
 	}
 	defer file.Close()
 
-	// 读取文件内容
-	fileContent, err := io.ReadAll(file)
-	if err != nil {
-		return nil, fmt.Errorf("读取文件失败: %w", err)
-	}
-
-	fileName := filepath.Base(filePath)
-	return s.UploadFileContent(ctx, fileName, fileContent)
+	return s.upload(ctx, filepath.Base(filePath), file)
 }
 
 // UploadFileContent 上传文件内容
 func (s *AssetService) UploadFileContent(ctx context.Context, fileName string, content []byte) (*Asset, error) {
-	if fileName == "" {
-		return nil, fmt.Errorf("文件名不能为空")
-	}
 	if len(content) == 0 {
 		return nil, fmt.Errorf("文件内容不能为空")
 	}
+	return s.upload(ctx, fileName, bytes.NewReader(content))
+}
 
-	// 创建multipart表单
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+// CreateAsset 上传素材（asset/create），返回素材URL
+// 支持 *os.File、*bytes.Buffer 等任意 io.Reader，内容以流式写入请求体，不会整体读入内存
+func (s *AssetService) CreateAsset(ctx context.Context, r io.Reader, filename string) (string, error) {
+	if r == nil {
+		return "", fmt.Errorf("文件内容不能为空")
+	}
 
-	// 添加文件字段
-	part, err := writer.CreateFormFile("file", fileName)
+	asset, err := s.upload(ctx, filename, r)
 	if err != nil {
-		return nil, fmt.Errorf("创建表单文件失败: %w", err)
+		return "", err
 	}
+	return asset.URL, nil
+}
 
-	_, err = part.Write(content)
+// upload 以 multipart/form-data 流式上传素材
+func (s *AssetService) upload(ctx context.Context, fileName string, r io.Reader) (*Asset, error) {
+	resp, err := s.client.postMultipart(ctx, "asset/create", nil, "file", fileName, r)
 	if err != nil {
-		return nil, fmt.Errorf("写入文件内容失败: %w", err)
+		return nil, err
 	}
 
-	writer.Close()
-
-	// 构建请求
-	url := s.client.buildURL("asset/create")
-	req, err := http.NewRequestWithContext(ctx, "POST", url, &buf)
-	if err != nil {
-		return nil, fmt.Errorf("创建请求失败: %w", err)
+	var asset Asset
+	if err := json.Unmarshal(resp.Data, &asset); err != nil {
+		return nil, fmt.Errorf("解析资源信息失败: %w", err)
 	}
 
-	// 设置请求头
-	req.Header.Set("Authorization", fmt.Sprintf("%s %s", s.client.tokenType, s.client.token))
-	req.Header.Set("User-Agent", UserAgent)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
+	s.client.logger.Infof("文件上传成功: %s -> %s", fileName, asset.URL)
+	return &asset, nil
+}
 
-	s.client.logger.Debugf("上传文件: %s", fileName)
+// postMultipart 以 multipart/form-data 流式提交表单，fields 为普通字段，文件内容从 r 读取写入 fileField 字段
+// 请求与其他API请求共用限流、拦截器、默认超时与重试；r 实现 io.Seeker（如 *os.File、*bytes.Reader）时
+// 重试会回到起始位置重新上传，否则内容只能读取一次，上传失败不会自动重试
+func (c *Client) postMultipart(ctx context.Context, endpoint string, fields map[string]string, fileField, fileName string, r io.Reader) (*Response, error) {
+	if fileName == "" {
+		return nil, fmt.Errorf("文件名不能为空")
+	}
 
-	// 执行请求
-	resp, err := s.client.httpClient.Do(req)
+	body, replayable, err := multipartBody(fields, fileField, fileName, r)
 	if err != nil {
-		s.client.logger.WithError(err).Errorf("上传文件失败")
-		return nil, fmt.Errorf("上传文件失败: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// 读取响应
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		s.client.logger.WithError(err).Errorf("读取上传响应失败")
-		return nil, fmt.Errorf("读取上传响应失败: %w", err)
+	retryConfig := c.retryConfig
+	if !replayable {
+		noRetry := *retryConfig
+		noRetry.MaxRetries = 0
+		retryConfig = &noRetry
 	}
 
-	s.client.logger.Debugf("文件上传响应: %s", string(respBody))
+	c.logger.Debugf("上传文件: %s -> %s", fileName, endpoint)
+	return c.send(ctx, "POST", endpoint, nil, body, retryConfig)
+}
 
-	// 解析响应
-	var response Response
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		return nil, fmt.Errorf("解析上传响应失败: %w", err)
+// multipartBody 构造流式写入的 multipart 请求体，每次调用都通过管道边写表单边发送
+// 内容可回到起始位置时 replayable 为 true，否则第二次调用返回错误
+func multipartBody(fields map[string]string, fileField, fileName string, r io.Reader) (body requestBody, replayable bool, err error) {
+	// 记录起始位置，重试时从这里重新读取
+	seeker, ok := r.(io.Seeker)
+	var offset int64
+	if ok {
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			ok = false
+		}
 	}
 
-	// 检查API错误
-	if response.Code != 0 {
-		err := &APIError{
-			Code:    response.Code,
-			Message: response.Message,
+	// 预读文件头用于推断内容类型
+	br := bufio.NewReaderSize(r, 512)
+	head, err := br.Peek(512)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, false, fmt.Errorf("读取文件失败: %w", err)
+	}
+	if len(head) == 0 {
+		return nil, false, fmt.Errorf("文件内容不能为空")
+	}
+	partHeader := assetPartHeader(fileField, fileName, head)
+
+	attempts := 0
+	body = func() (io.Reader, string, error) {
+		var content io.Reader = br
+		if attempts > 0 {
+			if !ok {
+				return nil, "", fmt.Errorf("上传内容只能读取一次，无法重新发送")
+			}
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, "", fmt.Errorf("重置上传内容失败: %w", err)
+			}
+			content = r
 		}
-		s.client.logger.WithError(err).Errorf("文件上传API错误")
-		return nil, err
+		attempts++
+
+		pr, pw := io.Pipe()
+		writer := multipart.NewWriter(pw)
+		go func() {
+			for name, value := range fields {
+				if err := writer.WriteField(name, value); err != nil {
+					pw.CloseWithError(fmt.Errorf("写入表单字段失败: %w", err))
+					return
+				}
+			}
+			part, err := writer.CreatePart(partHeader)
+			if err != nil {
+				pw.CloseWithError(fmt.Errorf("创建表单文件失败: %w", err))
+				return
+			}
+			if _, err := io.Copy(part, content); err != nil {
+				pw.CloseWithError(fmt.Errorf("写入文件内容失败: %w", err))
+				return
+			}
+			pw.CloseWithError(writer.Close())
+		}()
+		return pr, writer.FormDataContentType(), nil
 	}
+	return body, ok, nil
+}
 
-	var asset Asset
-	if err := json.Unmarshal(response.Data, &asset); err != nil {
-		return nil, fmt.Errorf("解析资源信息失败: %w", err)
+// assetPartHeader 构建 multipart 文件字段头，按扩展名或内容推断 Content-Type
+func assetPartHeader(fieldName, fileName string, head []byte) textproto.MIMEHeader {
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(fileName)))
+	if contentType == "" {
+		contentType = http.DetectContentType(head)
 	}
 
-	s.client.logger.Infof("文件上传成功: %s -> %s", fileName, asset.URL)
-	return &asset, nil
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, fieldName, assetFileNameEscaper.Replace(fileName)))
+	header.Set("Content-Type", contentType)
+	return header
 }
 
+var assetFileNameEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
 // 数据结构定义
 
 // Asset 媒体资源信息
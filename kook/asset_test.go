@@ -0,0 +1,59 @@
+package kook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadRetryResendsContent(t *testing.T) {
+	var attempts atomic.Int32
+	var lastFile []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		require.NoError(t, err)
+		lastFile, err = io.ReadAll(file)
+		require.NoError(t, err)
+
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"code":0,"message":"","data":{"url":"https://img.kookapp.cn/a.png"}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithLogger(NopLogger()),
+		WithoutRateLimit(), WithRetry(2, time.Millisecond))
+
+	content := []byte("hello asset")
+	url, err := client.Asset.CreateAsset(context.Background(), bytes.NewReader(content), "a.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "https://img.kookapp.cn/a.png", url)
+	assert.Equal(t, int32(2), attempts.Load())
+	assert.Equal(t, content, lastFile)
+}
+
+func TestUploadNonSeekableReaderIsNotRetried(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL), WithLogger(NopLogger()),
+		WithoutRateLimit(), WithRetry(2, time.Millisecond))
+
+	_, err := client.Asset.CreateAsset(context.Background(), io.MultiReader(bytes.NewReader([]byte("once"))), "a.txt")
+	require.Error(t, err)
+	assert.Equal(t, int32(1), attempts.Load())
+}
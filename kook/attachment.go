@@ -0,0 +1,181 @@
+package kook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// 附件下载被过滤条件拒绝时返回的错误
+var (
+	ErrAttachmentTypeNotAllowed = errors.New("附件类型不在允许范围内")
+	ErrAttachmentTooLarge       = errors.New("附件大小超过限制")
+)
+
+// DownloadOption 附件下载选项
+type DownloadOption func(*downloadOptions)
+
+type downloadOptions struct {
+	types   map[string]struct{}
+	maxSize int64
+}
+
+// WithAttachmentTypes 只下载指定类型的附件（image、file、video、audio 等），不设置时不限制
+func WithAttachmentTypes(types ...string) DownloadOption {
+	return func(o *downloadOptions) {
+		if o.types == nil {
+			o.types = make(map[string]struct{}, len(types))
+		}
+		for _, t := range types {
+			o.types[strings.ToLower(t)] = struct{}{}
+		}
+	}
+}
+
+// WithMaxAttachmentSize 设置附件大小上限（字节），0 表示不限制
+// 下载前先按附件信息中的 size 判断，下载过程中超过上限时读取返回 ErrAttachmentTooLarge
+func WithMaxAttachmentSize(n int64) DownloadOption {
+	return func(o *downloadOptions) {
+		o.maxSize = n
+	}
+}
+
+// Download 下载附件，调用方负责关闭返回的 ReadCloser
+// 复用 client 的 HTTP 连接池但不受其整体超时限制，下载耗时由 ctx 控制；
+// 附件位于 KOOK 域名或 API 同域时附带鉴权头，其他域名不发送 token
+func (a Attachment) Download(ctx context.Context, client *Client, opts ...DownloadOption) (io.ReadCloser, error) {
+	if client == nil {
+		return nil, fmt.Errorf("客户端不能为空")
+	}
+	if a.URL == "" {
+		return nil, fmt.Errorf("附件URL不能为空")
+	}
+
+	var o downloadOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.types != nil {
+		if _, ok := o.types[strings.ToLower(a.Type)]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrAttachmentTypeNotAllowed, a.Type)
+		}
+	}
+	if o.maxSize > 0 && int64(a.Size) > o.maxSize {
+		return nil, fmt.Errorf("%w: %d 字节", ErrAttachmentTooLarge, a.Size)
+	}
+
+	u, err := url.Parse(a.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return nil, fmt.Errorf("无效的附件URL: %s", a.URL)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, a.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	req.Header.Set("User-Agent", client.userAgent)
+	if isKOOKHost(u.Hostname()) || client.isAPIHost(u.Hostname()) {
+		auth, err := client.authorization(ctx)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", auth)
+	}
+
+	resp, err := client.streamingHTTPClient().Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("下载附件失败: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		drainAndClose(resp.Body)
+		return nil, fmt.Errorf("下载附件失败: HTTP %d", resp.StatusCode)
+	}
+	if o.maxSize > 0 && resp.ContentLength > o.maxSize {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: %d 字节", ErrAttachmentTooLarge, resp.ContentLength)
+	}
+
+	if o.maxSize > 0 {
+		return &limitedReadCloser{rc: resp.Body, remaining: o.maxSize}, nil
+	}
+	return resp.Body, nil
+}
+
+// DownloadToFile 下载附件并保存到 path
+// 先写入同目录下的临时文件，完整下载后再重命名，失败时不会留下不完整的文件
+func (a Attachment) DownloadToFile(ctx context.Context, client *Client, path string, opts ...DownloadOption) error {
+	body, err := a.Download(ctx, client, opts...)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("创建临时文件失败: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("写入附件失败: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("写入附件失败: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("保存附件失败: %w", err)
+	}
+	return nil
+}
+
+// isKOOKHost 判断是否为 KOOK 自有域名，只有这些域名才需要（且允许）携带 token
+func isKOOKHost(host string) bool {
+	host = strings.ToLower(host)
+	for _, domain := range []string{"kookapp.cn", "kaiheila.cn"} {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAPIHost 判断是否与客户端配置的 API 地址同域（如自建代理）
+func (c *Client) isAPIHost(host string) bool {
+	base, err := url.Parse(c.baseURL)
+	return err == nil && strings.EqualFold(base.Hostname(), host)
+}
+
+// limitedReadCloser 读取超过 remaining 字节时返回 ErrAttachmentTooLarge
+type limitedReadCloser struct {
+	rc        io.ReadCloser
+	remaining int64
+}
+
+// Read 实现 io.Reader
+func (l *limitedReadCloser) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, ErrAttachmentTooLarge
+	}
+	// 多读一个字节用于判断是否超限
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.rc.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n + int(l.remaining), ErrAttachmentTooLarge
+	}
+	return n, err
+}
+
+// Close 实现 io.Closer
+func (l *limitedReadCloser) Close() error {
+	return l.rc.Close()
+}
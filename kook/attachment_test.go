@@ -0,0 +1,42 @@
+package kook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAttachmentDownloadIgnoresClientTimeout 下载耗时超过 HTTP 客户端的 Timeout 时不被截断，只受 ctx 控制
+func TestAttachmentDownloadIgnoresClientTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("first,"))
+		w.(http.Flusher).Flush()
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("second"))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithLogger(NopLogger()), WithHTTPClient(&http.Client{Timeout: 50 * time.Millisecond}))
+	attachment := Attachment{Type: "file", URL: server.URL + "/a.txt"}
+
+	body, err := attachment.Download(context.Background(), client)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "first,second", string(data))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	body, err = attachment.Download(ctx, client)
+	require.NoError(t, err)
+	defer body.Close()
+	_, err = io.ReadAll(body)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
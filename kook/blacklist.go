@@ -12,7 +12,7 @@ type BlacklistService struct {
 	client *Client
 }
 
-// GetBlacklistUsers 获取屏蔽用户列表
+// GetBlacklistUsers 获取单页屏蔽用户列表，需要全部数据时使用 ListBlacklist
 func (s *BlacklistService) GetBlacklistUsers(ctx context.Context, guildID string, page, pageSize int) (*BlacklistResponse, error) {
 	if guildID == "" {
 		return nil, fmt.Errorf("服务器ID不能为空")
@@ -42,14 +42,30 @@ func (s *BlacklistService) GetBlacklistUsers(ctx context.Context, guildID string
 	return &result, nil
 }
 
-// CreateBlacklistUser 屏蔽用户
-func (s *BlacklistService) CreateBlacklistUser(ctx context.Context, guildID, userID string, remark string, delMsgDays int) error {
+// IterateBlacklist 创建服务器黑名单迭代器
+func (s *BlacklistService) IterateBlacklist(ctx context.Context, guildID string) *PageIterator[BlacklistUser] {
+	if guildID == "" {
+		return newPageIteratorError[BlacklistUser](fmt.Errorf("服务器ID不能为空"))
+	}
+	return newPageIterator[BlacklistUser](s.client, "blacklist/list", map[string]string{"guild_id": guildID}, 50)
+}
+
+// ListBlacklist 获取服务器的全部黑名单用户（自动翻页）
+func (s *BlacklistService) ListBlacklist(ctx context.Context, guildID string) ([]BlacklistUser, error) {
+	return s.IterateBlacklist(ctx, guildID).All(ctx)
+}
+
+// CreateBlacklist 把用户加入服务器黑名单（封禁），delMsgDays 为删除该用户最近几天的消息（0-7，0 表示不删除）
+func (s *BlacklistService) CreateBlacklist(ctx context.Context, guildID, userID, remark string, delMsgDays int) error {
 	if guildID == "" {
 		return fmt.Errorf("服务器ID不能为空")
 	}
 	if userID == "" {
 		return fmt.Errorf("用户ID不能为空")
 	}
+	if delMsgDays < 0 || delMsgDays > 7 {
+		return fmt.Errorf("删除消息天数必须在0-7之间")
+	}
 
 	params := map[string]interface{}{
 		"guild_id": guildID,
@@ -67,8 +83,15 @@ func (s *BlacklistService) CreateBlacklistUser(ctx context.Context, guildID, use
 	return err
 }
 
-// DeleteBlacklistUser 取消屏蔽用户
-func (s *BlacklistService) DeleteBlacklistUser(ctx context.Context, guildID, userID string) error {
+// CreateBlacklistUser 屏蔽用户
+//
+// Deprecated: 使用 CreateBlacklist
+func (s *BlacklistService) CreateBlacklistUser(ctx context.Context, guildID, userID string, remark string, delMsgDays int) error {
+	return s.CreateBlacklist(ctx, guildID, userID, remark, delMsgDays)
+}
+
+// DeleteBlacklist 把用户移出服务器黑名单（解封）
+func (s *BlacklistService) DeleteBlacklist(ctx context.Context, guildID, userID string) error {
 	if guildID == "" {
 		return fmt.Errorf("服务器ID不能为空")
 	}
@@ -85,15 +108,22 @@ func (s *BlacklistService) DeleteBlacklistUser(ctx context.Context, guildID, use
 	return err
 }
 
+// DeleteBlacklistUser 取消屏蔽用户
+//
+// Deprecated: 使用 DeleteBlacklist
+func (s *BlacklistService) DeleteBlacklistUser(ctx context.Context, guildID, userID string) error {
+	return s.DeleteBlacklist(ctx, guildID, userID)
+}
+
 // 数据结构定义
 
 // BlacklistUser 屏蔽用户信息
 type BlacklistUser struct {
-	User       User   `json:"user"`        // 用户信息
-	Remark     string `json:"remark"`      // 屏蔽备注
-	UserID     string `json:"user_id"`     // 用户ID
-	CreatedAt  int64  `json:"created_at"`  // 屏蔽时间
-	UpdatedAt  int64  `json:"updated_at"`  // 更新时间
+	User      User   `json:"user"`         // 用户信息
+	Remark    string `json:"remark"`       // 屏蔽备注
+	UserID    string `json:"user_id"`      // 用户ID
+	CreatedAt int64  `json:"created_time"` // 加入黑名单的时间（毫秒）
+	UpdatedAt int64  `json:"updated_at"`   // 更新时间
 }
 
 // BlacklistResponse 屏蔽用户列表响应
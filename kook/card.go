@@ -0,0 +1,383 @@
+package kook
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// 卡片主题常量
+const (
+	CardThemePrimary   = "primary"
+	CardThemeSuccess   = "success"
+	CardThemeDanger    = "danger"
+	CardThemeWarning   = "warning"
+	CardThemeInfo      = "info"
+	CardThemeSecondary = "secondary"
+	CardThemeNone      = "none"
+)
+
+// 卡片/图片尺寸常量
+const (
+	CardSizeSmall = "sm"
+	CardSizeLarge = "lg"
+)
+
+// CardElement 卡片元素（plain-text、kmarkdown、image、button、paragraph）
+type CardElement interface {
+	json.Marshaler
+	CardElementType() string
+}
+
+// CardModule 卡片模块
+type CardModule interface {
+	json.Marshaler
+	CardModuleType() string
+}
+
+// PlainTextElement 普通文本元素
+type PlainTextElement struct {
+	Content string
+	Emoji   bool // 是否把 :emoji: 转换为表情
+}
+
+// CardElementType 返回元素类型
+func (e PlainTextElement) CardElementType() string { return "plain-text" }
+
+// MarshalJSON 实现JSON序列化
+func (e PlainTextElement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Content string `json:"content"`
+		Emoji   bool   `json:"emoji,omitempty"`
+	}{e.CardElementType(), e.Content, e.Emoji})
+}
+
+// KMarkdownElement KMarkdown 文本元素
+type KMarkdownElement struct {
+	Content string
+}
+
+// CardElementType 返回元素类型
+func (e KMarkdownElement) CardElementType() string { return "kmarkdown" }
+
+// MarshalJSON 实现JSON序列化
+func (e KMarkdownElement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type    string `json:"type"`
+		Content string `json:"content"`
+	}{e.CardElementType(), e.Content})
+}
+
+// ImageElement 图片元素
+type ImageElement struct {
+	Src    string
+	Alt    string
+	Size   string // sm 或 lg
+	Circle bool
+}
+
+// CardElementType 返回元素类型
+func (e ImageElement) CardElementType() string { return "image" }
+
+// MarshalJSON 实现JSON序列化
+func (e ImageElement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string `json:"type"`
+		Src    string `json:"src"`
+		Alt    string `json:"alt,omitempty"`
+		Size   string `json:"size,omitempty"`
+		Circle bool   `json:"circle,omitempty"`
+	}{e.CardElementType(), e.Src, e.Alt, e.Size, e.Circle})
+}
+
+// ButtonElement 按钮元素
+type ButtonElement struct {
+	Theme string
+	Value string
+	Click string      // link 或 return-val，为空时按钮无点击行为
+	Text  CardElement // plain-text 或 kmarkdown
+}
+
+// CardElementType 返回元素类型
+func (e ButtonElement) CardElementType() string { return "button" }
+
+// MarshalJSON 实现JSON序列化
+func (e ButtonElement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string      `json:"type"`
+		Theme string      `json:"theme,omitempty"`
+		Value string      `json:"value,omitempty"`
+		Click string      `json:"click,omitempty"`
+		Text  CardElement `json:"text"`
+	}{e.CardElementType(), e.Theme, e.Value, e.Click, e.Text})
+}
+
+// ParagraphElement 区域文本元素（多列）
+type ParagraphElement struct {
+	Cols   int
+	Fields []CardElement
+}
+
+// CardElementType 返回元素类型
+func (e ParagraphElement) CardElementType() string { return "paragraph" }
+
+// MarshalJSON 实现JSON序列化
+func (e ParagraphElement) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type   string        `json:"type"`
+		Cols   int           `json:"cols"`
+		Fields []CardElement `json:"fields"`
+	}{e.CardElementType(), e.Cols, e.Fields})
+}
+
+// HeaderModule 标题模块
+type HeaderModule struct {
+	Text PlainTextElement
+}
+
+// CardModuleType 返回模块类型
+func (m HeaderModule) CardModuleType() string { return "header" }
+
+// MarshalJSON 实现JSON序列化
+func (m HeaderModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type string           `json:"type"`
+		Text PlainTextElement `json:"text"`
+	}{m.CardModuleType(), m.Text})
+}
+
+// SectionModule 内容模块
+type SectionModule struct {
+	Mode      string      // left 或 right，配合 Accessory 使用
+	Text      CardElement // plain-text、kmarkdown 或 paragraph
+	Accessory CardElement // image 或 button，可为空
+}
+
+// CardModuleType 返回模块类型
+func (m SectionModule) CardModuleType() string { return "section" }
+
+// MarshalJSON 实现JSON序列化
+func (m SectionModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      string      `json:"type"`
+		Mode      string      `json:"mode,omitempty"`
+		Text      CardElement `json:"text"`
+		Accessory CardElement `json:"accessory,omitempty"`
+	}{m.CardModuleType(), m.Mode, m.Text, m.Accessory})
+}
+
+// ImageGroupModule 图片组模块
+type ImageGroupModule struct {
+	Elements []ImageElement
+}
+
+// CardModuleType 返回模块类型
+func (m ImageGroupModule) CardModuleType() string { return "image-group" }
+
+// MarshalJSON 实现JSON序列化
+func (m ImageGroupModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     string         `json:"type"`
+		Elements []ImageElement `json:"elements"`
+	}{m.CardModuleType(), m.Elements})
+}
+
+// ContainerModule 容器模块（图片按原比例展示）
+type ContainerModule struct {
+	Elements []ImageElement
+}
+
+// CardModuleType 返回模块类型
+func (m ContainerModule) CardModuleType() string { return "container" }
+
+// MarshalJSON 实现JSON序列化
+func (m ContainerModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     string         `json:"type"`
+		Elements []ImageElement `json:"elements"`
+	}{m.CardModuleType(), m.Elements})
+}
+
+// ActionGroupModule 交互模块
+type ActionGroupModule struct {
+	Elements []ButtonElement
+}
+
+// CardModuleType 返回模块类型
+func (m ActionGroupModule) CardModuleType() string { return "action-group" }
+
+// MarshalJSON 实现JSON序列化
+func (m ActionGroupModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     string          `json:"type"`
+		Elements []ButtonElement `json:"elements"`
+	}{m.CardModuleType(), m.Elements})
+}
+
+// ContextModule 备注模块
+type ContextModule struct {
+	Elements []CardElement // plain-text、kmarkdown 或 image
+}
+
+// CardModuleType 返回模块类型
+func (m ContextModule) CardModuleType() string { return "context" }
+
+// MarshalJSON 实现JSON序列化
+func (m ContextModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type     string        `json:"type"`
+		Elements []CardElement `json:"elements"`
+	}{m.CardModuleType(), m.Elements})
+}
+
+// DividerModule 分割线模块
+type DividerModule struct{}
+
+// CardModuleType 返回模块类型
+func (m DividerModule) CardModuleType() string { return "divider" }
+
+// MarshalJSON 实现JSON序列化
+func (m DividerModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type string `json:"type"`
+	}{m.CardModuleType()})
+}
+
+// CountdownModule 倒计时模块
+type CountdownModule struct {
+	Mode      string // day、hour 或 second
+	EndTime   int64  // 结束时间（毫秒时间戳）
+	StartTime int64  // 开始时间（毫秒时间戳），仅 second 模式需要
+}
+
+// CardModuleType 返回模块类型
+func (m CountdownModule) CardModuleType() string { return "countdown" }
+
+// MarshalJSON 实现JSON序列化
+func (m CountdownModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type      string `json:"type"`
+		Mode      string `json:"mode"`
+		EndTime   int64  `json:"endTime"`
+		StartTime int64  `json:"startTime,omitempty"`
+	}{m.CardModuleType(), m.Mode, m.EndTime, m.StartTime})
+}
+
+// FileModule 文件/音频/视频模块
+type FileModule struct {
+	Kind  string // file、audio 或 video
+	Title string
+	Src   string
+	Cover string // 音频封面，仅 audio 有效
+}
+
+// CardModuleType 返回模块类型
+func (m FileModule) CardModuleType() string {
+	if m.Kind == "" {
+		return "file"
+	}
+	return m.Kind
+}
+
+// MarshalJSON 实现JSON序列化
+func (m FileModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		Title string `json:"title,omitempty"`
+		Src   string `json:"src"`
+		Cover string `json:"cover,omitempty"`
+	}{m.CardModuleType(), m.Title, m.Src, m.Cover})
+}
+
+// InviteModule 邀请模块
+type InviteModule struct {
+	Code string
+}
+
+// CardModuleType 返回模块类型
+func (m InviteModule) CardModuleType() string { return "invite" }
+
+// MarshalJSON 实现JSON序列化
+func (m InviteModule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Type string `json:"type"`
+		Code string `json:"code"`
+	}{m.CardModuleType(), m.Code})
+}
+
+// Card 卡片
+type Card struct {
+	Theme   string
+	Size    string
+	Color   string // 左侧边框颜色，如 #aaaaaa
+	Modules []CardModule
+}
+
+// NewCard 创建卡片
+func NewCard(theme, size string) *Card {
+	return &Card{
+		Theme: theme,
+		Size:  size,
+	}
+}
+
+// AddModule 添加模块
+func (c *Card) AddModule(module CardModule) *Card {
+	c.Modules = append(c.Modules, module)
+	return c
+}
+
+// MarshalJSON 实现JSON序列化
+func (c Card) MarshalJSON() ([]byte, error) {
+	modules := c.Modules
+	if modules == nil {
+		modules = []CardModule{}
+	}
+	return json.Marshal(struct {
+		Type    string       `json:"type"`
+		Theme   string       `json:"theme,omitempty"`
+		Size    string       `json:"size,omitempty"`
+		Color   string       `json:"color,omitempty"`
+		Modules []CardModule `json:"modules"`
+	}{"card", c.Theme, c.Size, c.Color, modules})
+}
+
+// CardMessage 卡片消息（card 数组）
+type CardMessage []*Card
+
+// NewCardMessage 用若干卡片创建卡片消息
+func NewCardMessage(cards ...*Card) CardMessage {
+	return CardMessage(cards)
+}
+
+// String 返回可直接作为 SendCardMessage 内容的 JSON 数组字符串，序列化失败时返回空字符串
+func (m CardMessage) String() string {
+	data, err := m.JSON()
+	if err != nil {
+		return ""
+	}
+	return data
+}
+
+// JSON 校验卡片数量后序列化为 JSON 数组字符串
+func (m CardMessage) JSON() (string, error) {
+	if len(m) == 0 {
+		return "", fmt.Errorf("卡片消息至少包含一个 card")
+	}
+	if len(m) > MaxCardsPerMessage {
+		return "", NewValidationErrorWithValue("content",
+			fmt.Sprintf("卡片消息最多包含 %d 个 card", MaxCardsPerMessage), strconv.Itoa(len(m)))
+	}
+	for i, card := range m {
+		if card == nil {
+			return "", fmt.Errorf("第 %d 个 card 为空", i+1)
+		}
+	}
+
+	data, err := json.Marshal([]*Card(m))
+	if err != nil {
+		return "", fmt.Errorf("序列化卡片消息失败: %w", err)
+	}
+	return string(data), nil
+}
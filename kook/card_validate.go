@@ -0,0 +1,333 @@
+package kook
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// 卡片结构的本地校验阈值，与 KOOK 卡片消息文档保持一致
+const (
+	maxCardHeaderLength    = 100
+	maxCardPlainTextLength = 2000
+	maxCardKMarkdownLength = 5000
+	maxParagraphCols       = 3
+	maxParagraphFields     = 50
+	maxCardGroupImages     = 9
+	maxActionGroupButtons  = 4
+	maxContextElements     = 10
+)
+
+// Build 校验卡片结构后返回卡片本身，便于链式调用结束时发现嵌套错误
+//
+//	card, err := kook.NewCard(kook.CardThemePrimary, kook.CardSizeLarge).
+//		AddModule(...).
+//		Build()
+func (c *Card) Build() (*Card, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate 按 KOOK 的嵌套规则在本地校验卡片结构
+// 违反规则时返回 *ValidationError，Field 指出出错的 module 及其中的位置，如 modules[2].accessory
+func (c *Card) Validate() error {
+	return c.validate("")
+}
+
+// Build 校验所有卡片的结构并序列化为可直接发送的 JSON 数组字符串
+func (m CardMessage) Build() (string, error) {
+	if err := m.Validate(); err != nil {
+		return "", err
+	}
+	return m.JSON()
+}
+
+// Validate 校验卡片数量以及每张卡片的结构，Field 以 cards[i] 开头
+func (m CardMessage) Validate() error {
+	if len(m) == 0 {
+		return fmt.Errorf("卡片消息至少包含一个 card")
+	}
+	if len(m) > MaxCardsPerMessage {
+		return NewValidationErrorWithValue("content",
+			fmt.Sprintf("卡片消息最多包含 %d 个 card", MaxCardsPerMessage), strconv.Itoa(len(m)))
+	}
+
+	modules := 0
+	for i, card := range m {
+		if card == nil {
+			return NewValidationError(fmt.Sprintf("cards[%d]", i), "card 不能为空")
+		}
+		if err := card.validate(fmt.Sprintf("cards[%d].", i)); err != nil {
+			return err
+		}
+		modules += len(card.Modules)
+	}
+	if modules > MaxCardModules {
+		return NewValidationErrorWithValue("content",
+			fmt.Sprintf("卡片消息的 module 总数不能超过 %d", MaxCardModules), strconv.Itoa(modules))
+	}
+	return nil
+}
+
+// validate 校验单张卡片，prefix 为字段路径前缀
+func (c *Card) validate(prefix string) error {
+	if c == nil {
+		return NewValidationError(strings.TrimSuffix(prefix, "."), "card 不能为空")
+	}
+	switch c.Theme {
+	case "", CardThemePrimary, CardThemeSuccess, CardThemeDanger, CardThemeWarning,
+		CardThemeInfo, CardThemeSecondary, CardThemeNone:
+	default:
+		return NewValidationErrorWithValue(prefix+"theme", "无效的卡片主题", c.Theme)
+	}
+	if err := validateCardSize(prefix+"size", c.Size); err != nil {
+		return err
+	}
+	if len(c.Modules) > MaxCardModules {
+		return NewValidationErrorWithValue(prefix+"modules",
+			fmt.Sprintf("单个 card 的 module 数不能超过 %d", MaxCardModules), strconv.Itoa(len(c.Modules)))
+	}
+
+	for i, module := range c.Modules {
+		if err := validateCardModule(fmt.Sprintf("%smodules[%d]", prefix, i), module); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCardModule 按模块类型校验其中允许出现的元素
+// 非 SDK 内置的模块类型不做校验，原样交给服务端
+func validateCardModule(field string, module CardModule) error {
+	module, ok := derefCardValue(module)
+	if !ok {
+		return NewValidationError(field, "module 不能为空")
+	}
+
+	switch m := module.(type) {
+	case HeaderModule:
+		return validateCardText(field+".text", m.Text, maxCardHeaderLength)
+	case SectionModule:
+		return validateSectionModule(field, m)
+	case ImageGroupModule:
+		return validateImageList(field, "image-group", m.Elements)
+	case ContainerModule:
+		return validateImageList(field, "container", m.Elements)
+	case ActionGroupModule:
+		if len(m.Elements) == 0 || len(m.Elements) > maxActionGroupButtons {
+			return NewValidationErrorWithValue(field+".elements",
+				fmt.Sprintf("action-group 模块需要 1-%d 个 button", maxActionGroupButtons), strconv.Itoa(len(m.Elements)))
+		}
+		for i, button := range m.Elements {
+			if err := validateButton(fmt.Sprintf("%s.elements[%d]", field, i), button); err != nil {
+				return err
+			}
+		}
+	case ContextModule:
+		if len(m.Elements) == 0 || len(m.Elements) > maxContextElements {
+			return NewValidationErrorWithValue(field+".elements",
+				fmt.Sprintf("context 模块需要 1-%d 个元素", maxContextElements), strconv.Itoa(len(m.Elements)))
+		}
+		for i, element := range m.Elements {
+			elemField := fmt.Sprintf("%s.elements[%d]", field, i)
+			if err := validateCardElement(elemField, "context 模块的元素", element, "plain-text", "kmarkdown", "image"); err != nil {
+				return err
+			}
+		}
+	case DividerModule:
+	case CountdownModule:
+		switch m.Mode {
+		case "day", "hour":
+		case "second":
+			if m.StartTime <= 0 {
+				return NewValidationError(field+".startTime", "second 模式的倒计时必须设置开始时间")
+			}
+		default:
+			return NewValidationErrorWithValue(field+".mode", "倒计时模式只能是 day、hour 或 second", m.Mode)
+		}
+		if m.EndTime <= 0 {
+			return NewValidationError(field+".endTime", "倒计时必须设置结束时间")
+		}
+		if m.StartTime > 0 && m.StartTime >= m.EndTime {
+			return NewValidationError(field+".startTime", "倒计时开始时间必须早于结束时间")
+		}
+	case FileModule:
+		switch m.CardModuleType() {
+		case "file", "audio", "video":
+		default:
+			return NewValidationErrorWithValue(field+".type", "文件模块类型只能是 file、audio 或 video", m.Kind)
+		}
+		if m.Src == "" {
+			return NewValidationError(field+".src", "文件地址不能为空")
+		}
+		if m.Cover != "" && m.CardModuleType() != "audio" {
+			return NewValidationErrorWithValue(field+".cover", "只有 audio 模块可以设置封面", m.CardModuleType())
+		}
+	case InviteModule:
+		if m.Code == "" {
+			return NewValidationError(field+".code", "邀请码不能为空")
+		}
+	}
+	return nil
+}
+
+// validateSectionModule section 的 text 只能是文本或 paragraph，accessory 只能是 image 或 button
+func validateSectionModule(field string, m SectionModule) error {
+	if err := validateCardElement(field+".text", "section 模块的 text", m.Text, "plain-text", "kmarkdown", "paragraph"); err != nil {
+		return err
+	}
+
+	switch m.Mode {
+	case "", "left", "right":
+	default:
+		return NewValidationErrorWithValue(field+".mode", "section 模块的 mode 只能是 left 或 right", m.Mode)
+	}
+
+	if m.Accessory == nil {
+		return nil
+	}
+	if err := validateCardElement(field+".accessory", "section 模块的 accessory", m.Accessory, "image", "button"); err != nil {
+		return err
+	}
+	if m.Accessory.CardElementType() == "button" && m.Mode == "left" {
+		return NewValidationErrorWithValue(field+".mode", "button 只能放在 section 的右侧", m.Mode)
+	}
+	return nil
+}
+
+// validateImageList 校验 image-group 与 container 中的图片
+func validateImageList(field, kind string, images []ImageElement) error {
+	if len(images) == 0 || len(images) > maxCardGroupImages {
+		return NewValidationErrorWithValue(field+".elements",
+			fmt.Sprintf("%s 模块需要 1-%d 张图片", kind, maxCardGroupImages), strconv.Itoa(len(images)))
+	}
+	for i, image := range images {
+		if err := validateImage(fmt.Sprintf("%s.elements[%d]", field, i), image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateCardElement 校验元素类型是否在 allowed 中，再校验元素自身的内容
+func validateCardElement(field, owner string, element CardElement, allowed ...string) error {
+	element, ok := derefCardValue(element)
+	if !ok {
+		return NewValidationError(field, owner+" 不能为空")
+	}
+
+	typ := element.CardElementType()
+	permitted := false
+	for _, a := range allowed {
+		if typ == a {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		return NewValidationErrorWithValue(field, fmt.Sprintf("%s 只能是 %s", owner, strings.Join(allowed, "、")), typ)
+	}
+
+	switch e := element.(type) {
+	case PlainTextElement:
+		return validateCardText(field, e, maxCardPlainTextLength)
+	case KMarkdownElement:
+		if n := utf8.RuneCountInString(e.Content); n > maxCardKMarkdownLength {
+			return NewValidationErrorWithValue(field+".content",
+				fmt.Sprintf("kmarkdown 内容不能超过 %d 字符", maxCardKMarkdownLength), strconv.Itoa(n))
+		}
+	case ImageElement:
+		return validateImage(field, e)
+	case ButtonElement:
+		return validateButton(field, e)
+	case ParagraphElement:
+		return validateParagraph(field, e)
+	}
+	return nil
+}
+
+// validateCardText 校验 plain-text 的长度
+func validateCardText(field string, text PlainTextElement, max int) error {
+	if n := utf8.RuneCountInString(text.Content); n > max {
+		return NewValidationErrorWithValue(field+".content",
+			fmt.Sprintf("plain-text 内容不能超过 %d 字符", max), strconv.Itoa(n))
+	}
+	return nil
+}
+
+// validateParagraph paragraph 支持 1-3 列，fields 只能是 plain-text 或 kmarkdown
+func validateParagraph(field string, p ParagraphElement) error {
+	if p.Cols < 1 || p.Cols > maxParagraphCols {
+		return NewValidationErrorWithValue(field+".cols",
+			fmt.Sprintf("paragraph 的列数只能是 1-%d", maxParagraphCols), strconv.Itoa(p.Cols))
+	}
+	if len(p.Fields) == 0 || len(p.Fields) > maxParagraphFields {
+		return NewValidationErrorWithValue(field+".fields",
+			fmt.Sprintf("paragraph 需要 1-%d 个 field", maxParagraphFields), strconv.Itoa(len(p.Fields)))
+	}
+	for i, f := range p.Fields {
+		if err := validateCardElement(fmt.Sprintf("%s.fields[%d]", field, i), "paragraph 的 field", f, "plain-text", "kmarkdown"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateButton 按钮文字只能是 plain-text 或 kmarkdown，link 类型必须带跳转地址
+func validateButton(field string, b ButtonElement) error {
+	switch b.Theme {
+	case "", CardThemePrimary, CardThemeSuccess, CardThemeDanger, CardThemeWarning,
+		CardThemeInfo, CardThemeSecondary, CardThemeNone:
+	default:
+		return NewValidationErrorWithValue(field+".theme", "无效的按钮主题", b.Theme)
+	}
+	switch b.Click {
+	case "", "return-val":
+	case "link":
+		if b.Value == "" {
+			return NewValidationError(field+".value", "link 类型按钮必须设置跳转地址")
+		}
+	default:
+		return NewValidationErrorWithValue(field+".click", "按钮点击行为只能是 link 或 return-val", b.Click)
+	}
+	return validateCardElement(field+".text", "button 的 text", b.Text, "plain-text", "kmarkdown")
+}
+
+// validateImage 图片地址不能为空
+func validateImage(field string, image ImageElement) error {
+	if image.Src == "" {
+		return NewValidationError(field+".src", "图片地址不能为空")
+	}
+	return validateCardSize(field+".size", image.Size)
+}
+
+// validateCardSize 尺寸只能为空、sm 或 lg
+func validateCardSize(field, size string) error {
+	switch size {
+	case "", CardSizeSmall, CardSizeLarge:
+		return nil
+	default:
+		return NewValidationErrorWithValue(field, "尺寸只能是 sm 或 lg", size)
+	}
+}
+
+// derefCardValue 把指针形式的模块/元素解引用为值，nil 时返回 false
+func derefCardValue[T any](v T) (T, bool) {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return v, false
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return v, false
+		}
+		if elem, ok := rv.Elem().Interface().(T); ok {
+			return elem, true
+		}
+	}
+	return v, true
+}
@@ -45,6 +45,14 @@ func (s *ChannelService) GetChannelList(ctx context.Context, guildID string, pag
 	return &result, nil
 }
 
+// IterateChannels 创建服务器频道列表迭代器
+func (s *ChannelService) IterateChannels(ctx context.Context, guildID string) *PageIterator[Channel] {
+	if guildID == "" {
+		return newPageIteratorError[Channel](fmt.Errorf("服务器ID不能为空"))
+	}
+	return newPageIterator[Channel](s.client, "channel/list", map[string]string{"guild_id": guildID}, 50)
+}
+
 // GetChannelInfo 获取频道信息
 func (s *ChannelService) GetChannelInfo(ctx context.Context, channelID string) (*Channel, error) {
 	if channelID == "" {
@@ -68,6 +76,16 @@ func (s *ChannelService) GetChannelInfo(ctx context.Context, channelID string) (
 	return &channel, nil
 }
 
+// GetChannel 获取频道信息
+func (s *ChannelService) GetChannel(ctx context.Context, channelID string) (*Channel, error) {
+	return s.GetChannelInfo(ctx, channelID)
+}
+
+// ListChannels 获取服务器的全部频道（自动翻页）
+func (s *ChannelService) ListChannels(ctx context.Context, guildID string) ([]Channel, error) {
+	return s.IterateChannels(ctx, guildID).All(ctx)
+}
+
 // CreateChannel 创建频道
 func (s *ChannelService) CreateChannel(ctx context.Context, guildID string, params CreateChannelParams) (*Channel, error) {
 	if guildID == "" {
@@ -85,7 +103,7 @@ func (s *ChannelService) CreateChannel(ctx context.Context, guildID string, para
 	if params.Type > 0 {
 		requestParams["type"] = params.Type
 	} else {
-		requestParams["type"] = 1 // 默认为文字频道
+		requestParams["type"] = ChannelTypeText // 默认为文字频道
 	}
 
 	if params.ParentID != "" {
@@ -288,13 +306,20 @@ func (s *ChannelService) SyncChannelRole(ctx context.Context, channelID string)
 	return &result, nil
 }
 
+// 语音频道质量
+const (
+	VoiceQualitySmooth = 1 // 流畅
+	VoiceQualityNormal = 2 // 正常
+	VoiceQualityHigh   = 3 // 高质量
+)
+
 // CreateChannelParams 创建频道参数
 type CreateChannelParams struct {
 	Name         string `json:"name"`                   // 频道名称
-	Type         int    `json:"type,omitempty"`         // 频道类型：1文字，2语音
+	Type         int    `json:"type,omitempty"`         // 频道类型：ChannelTypeText 或 ChannelTypeVoice
 	ParentID     string `json:"parent_id,omitempty"`    // 父分组ID
 	LimitAmount  int    `json:"limit_amount,omitempty"` // 语音频道人数限制
-	VoiceQuality int    `json:"voice_quality,omitempty"`// 语音质量
+	VoiceQuality int    `json:"voice_quality,omitempty"`// 语音质量：VoiceQualitySmooth/Normal/High
 	IsCategory   bool   `json:"is_category,omitempty"`  // 是否为分组
 }
 
@@ -320,4 +345,109 @@ type ChannelRoleResponse struct {
 	PermissionOverwrites []PermissionOverwrite `json:"permission_overwrites"`
 	PermissionUsers      []PermissionUser      `json:"permission_users"`
 }
- 
\ No newline at end of file
+ 
+// ChannelPermissionResult 频道权限覆盖的创建/更新结果
+type ChannelPermissionResult struct {
+	RoleID int    `json:"role_id,omitempty"` // 角色ID（角色覆盖）
+	UserID string `json:"user_id,omitempty"` // 用户ID（用户覆盖）
+	Allow  int    `json:"allow"`             // 允许的权限
+	Deny   int    `json:"deny"`              // 拒绝的权限
+}
+
+// GetChannelPermissions 获取频道的角色与用户权限覆盖
+func (s *ChannelService) GetChannelPermissions(ctx context.Context, channelID string) (*ChannelRoleResponse, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("频道ID不能为空")
+	}
+
+	resp, err := s.client.Get(ctx, "channel-role/index", map[string]string{"channel_id": channelID})
+	if err != nil {
+		return nil, err
+	}
+
+	var result ChannelRoleResponse
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("解析频道权限失败: %w", err)
+	}
+
+	return &result, nil
+}
+
+// CreateChannelRolePermission 为角色创建频道权限覆盖
+func (s *ChannelService) CreateChannelRolePermission(ctx context.Context, channelID string, roleID int) (*ChannelPermissionResult, error) {
+	return s.channelPermission(ctx, "channel-role/create", channelID, "role_id", strconv.Itoa(roleID), nil)
+}
+
+// CreateChannelUserPermission 为用户创建频道权限覆盖
+func (s *ChannelService) CreateChannelUserPermission(ctx context.Context, channelID, userID string) (*ChannelPermissionResult, error) {
+	return s.channelPermission(ctx, "channel-role/create", channelID, "user_id", userID, nil)
+}
+
+// UpdateChannelRolePermission 更新角色的频道权限覆盖，allow/deny 为权限位组合（Perm* 常量）
+func (s *ChannelService) UpdateChannelRolePermission(ctx context.Context, channelID string, roleID, allow, deny int) (*ChannelPermissionResult, error) {
+	return s.channelPermission(ctx, "channel-role/update", channelID, "role_id", strconv.Itoa(roleID), []int{allow, deny})
+}
+
+// UpdateChannelUserPermission 更新用户的频道权限覆盖，allow/deny 为权限位组合（Perm* 常量）
+func (s *ChannelService) UpdateChannelUserPermission(ctx context.Context, channelID, userID string, allow, deny int) (*ChannelPermissionResult, error) {
+	return s.channelPermission(ctx, "channel-role/update", channelID, "user_id", userID, []int{allow, deny})
+}
+
+// DeleteChannelRolePermission 删除角色的频道权限覆盖
+func (s *ChannelService) DeleteChannelRolePermission(ctx context.Context, channelID string, roleID int) error {
+	_, err := s.channelPermission(ctx, "channel-role/delete", channelID, "role_id", strconv.Itoa(roleID), nil)
+	return err
+}
+
+// DeleteChannelUserPermission 删除用户的频道权限覆盖
+func (s *ChannelService) DeleteChannelUserPermission(ctx context.Context, channelID, userID string) error {
+	_, err := s.channelPermission(ctx, "channel-role/delete", channelID, "user_id", userID, nil)
+	return err
+}
+
+// channelPermission 调用 channel-role 系列接口，targetType 为 role_id 或 user_id，perms 为 [allow, deny]
+func (s *ChannelService) channelPermission(ctx context.Context, endpoint, channelID, targetType, value string, perms []int) (*ChannelPermissionResult, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("频道ID不能为空")
+	}
+	if value == "" {
+		if targetType == "user_id" {
+			return nil, fmt.Errorf("用户ID不能为空")
+		}
+		return nil, fmt.Errorf("角色ID不能为空")
+	}
+
+	params := map[string]interface{}{
+		"channel_id": channelID,
+		"type":       targetType,
+		"value":      value,
+	}
+	if len(perms) == 2 {
+		params["allow"] = perms[0]
+		params["deny"] = perms[1]
+	}
+
+	resp, err := s.client.Post(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+	// 接口没有返回数据（删除接口总是如此）时按请求参数构造结果，调用方无需判断 nil
+	var result ChannelPermissionResult
+	if targetType == "user_id" {
+		result.UserID = value
+	} else {
+		result.RoleID, _ = strconv.Atoi(value)
+	}
+	if len(perms) == 2 {
+		result.Allow, result.Deny = perms[0], perms[1]
+	}
+	if endpoint == "channel-role/delete" || len(resp.Data) == 0 || string(resp.Data) == "null" || string(resp.Data) == "[]" {
+		return &result, nil
+	}
+
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("解析频道权限结果失败: %w", err)
+	}
+
+	return &result, nil
+}
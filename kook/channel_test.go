@@ -0,0 +1,30 @@
+package kook
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChannelPermissionEmptyData 接口返回空数据时按请求参数返回非 nil 的结果
+func TestChannelPermissionEmptyData(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"message":"","data":[]}`))
+	}))
+	defer server.Close()
+	client := NewClient("token", WithBaseURL(server.URL+"/api"), WithLogger(NopLogger()), WithoutRateLimit())
+
+	result, err := client.Channel.UpdateChannelRolePermission(context.Background(), "channel", 7, PermSendMessages, PermAddReactions)
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, ChannelPermissionResult{RoleID: 7, Allow: PermSendMessages, Deny: PermAddReactions}, *result)
+
+	result, err = client.Channel.CreateChannelUserPermission(context.Background(), "channel", "user")
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "user", result.UserID)
+}
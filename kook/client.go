@@ -4,14 +4,16 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/sirupsen/logrus"
 )
 
 const (
@@ -19,18 +21,27 @@ const (
 	BaseURL = "https://www.kookapp.cn/api"
 	// Version API版本
 	Version = "v3"
-	// UserAgent 用户代理
-	UserAgent = "KOOK-Go-SDK/1.0.0"
+	// SDKVersion SDK版本
+	SDKVersion = "1.0.0"
+	// UserAgent 默认的用户代理，可通过 WithUserAgent 覆盖
+	UserAgent = "kook.go/v" + SDKVersion
+	// DefaultHTTPTimeout 默认HTTP客户端超时，素材上传共用该客户端，上传大文件时可通过 WithHTTPClient 调整
+	DefaultHTTPTimeout = 10 * time.Second
 )
 
 // TokenType 鉴权类型
 type TokenType string
 
 const (
-	// TokenTypeBot 机器人Token
-	TokenTypeBot TokenType = "Bot"
-	// TokenTypeBearer OAuth2 Token
-	TokenTypeBearer TokenType = "Bearer"
+	// TokenBot 机器人Token
+	TokenBot TokenType = "Bot"
+	// TokenOAuth 用户级 OAuth2 Token，鉴权头使用 Bearer
+	TokenOAuth TokenType = "Bearer"
+
+	// TokenTypeBot 机器人Token，同 TokenBot
+	TokenTypeBot = TokenBot
+	// TokenTypeBearer OAuth2 Token，同 TokenOAuth
+	TokenTypeBearer = TokenOAuth
 )
 
 // Client KOOK API客户端
@@ -38,52 +49,111 @@ type Client struct {
 	httpClient  *http.Client
 	token       string
 	tokenType   TokenType
+	tokenFunc   func(ctx context.Context) (string, error)
 	baseURL     string
-	logger      *logrus.Logger
+	userAgent   string
+	logger      Logger
 	rateLimiter *GlobalRateLimiter
 	retryConfig *RetryConfig
 
+	// 请求默认超时，仅在调用方的 ctx 没有 deadline 时生效
+	defaultTimeout time.Duration
+
+	// 基于响应头的 bucket 限流
+	bucketLimiterEnabled bool
+	bucketLimiter        *BucketRateLimiter
+	onRateLimitWait      RateLimitWaitFunc
+
+	// 批量操作并发度
+	bulkConcurrency int
+
+	// 发送消息的 nonce 去重缓存，为空表示未开启；由下面的配置在选项应用后创建
+	nonceCache        *nonceCache
+	idempotency       bool
+	idempotencyTTL    time.Duration
+	idempotencyMaxLen int
+
+	// 发送前是否把文本中的 :shortcode: 替换为 unicode emoji
+	emojiShortcodes bool
+
+	// 分发消息事件前是否丢弃机器人自己发出的消息
+	ignoreSelf bool
+
+	// 最近创建的网关连接
+	gateway atomic.Pointer[WebSocketClient]
+	// 尚未关闭的网关连接，Client.Close 时统一关闭
+	gatewaysMu sync.Mutex
+	gateways   map[*WebSocketClient]struct{}
+	closed     atomic.Bool
+
+	// 指标收集
+	metrics MetricsCollector
+
+	// 请求/响应拦截器
+	requestInterceptors  []RequestInterceptor
+	responseInterceptors []ResponseInterceptor
+	sendHooks            []SendHook
+
 	// API服务
-	User      *UserService
-	Guild     *GuildService
-	Channel   *ChannelService
-	Message   *MessageService
-	Gateway   *GatewayService
-	Role      *RoleService
-	Game      *GameService
-	Friend    *FriendService
-	Invite    *InviteService
-	Asset     *AssetService
-	Intimacy  *IntimacyService
-	Badge     *BadgeService
-	Blacklist *BlacklistService
-	Emoji     *EmojiService
-	Region    *RegionService
-	OAuth     *OAuthService
-	Live      *LiveService
-	Admin     *AdminService
-	Security  *SecurityService
-	Voice     *VoiceService
-	Item      *ItemService
-	Order     *OrderService
-	Coupon    *CouponService
-	Boost     *BoostService
+	User          *UserService
+	Guild         *GuildService
+	Channel       *ChannelService
+	Message       *MessageService
+	DirectMessage *DirectMessageService
+	Gateway       *GatewayService
+	Role          *RoleService
+	Game          *GameService
+	Friend        *FriendService
+	Invite        *InviteService
+	Asset         *AssetService
+	Intimacy      *IntimacyService
+	Badge         *BadgeService
+	Blacklist     *BlacklistService
+	Emoji         *EmojiService
+	GuildEmoji    *GuildEmojiService
+	Region        *RegionService
+	OAuth         *OAuthService
+	Live          *LiveService
+	Admin         *AdminService
+	Security      *SecurityService
+	Voice         *VoiceService
+	Item          *ItemService
+	Order         *OrderService
+	Coupon        *CouponService
+	Boost         *BoostService
+	Template      *TemplateService
 }
 
 // ClientOption 客户端配置选项
 type ClientOption func(*Client)
 
-// WithHTTPClient 设置自定义HTTP客户端
+// WithHTTPClient 设置自定义HTTP客户端，可用于配置代理、超时、连接池或 mTLS
+// 限流与重试逻辑仍在该客户端之上生效；传入 nil 时保留默认客户端（超时 DefaultHTTPTimeout）
 func WithHTTPClient(httpClient *http.Client) ClientOption {
 	return func(c *Client) {
-		c.httpClient = httpClient
+		if httpClient != nil {
+			c.httpClient = httpClient
+		}
 	}
 }
 
-// WithTokenType 设置Token类型
+// WithTokenType 设置Token类型：TokenBot（默认）或 TokenOAuth
+// 所有 HTTP 请求、文件上传与网关连接的 Authorization 头都按该类型生成
 func WithTokenType(tokenType TokenType) ClientOption {
 	return func(c *Client) {
-		c.tokenType = tokenType
+		if tokenType != "" {
+			c.tokenType = tokenType
+		}
+	}
+}
+
+// WithTokenProvider 设置动态获取 token 的函数，用于 token 轮换（如 OAuth）时不重启进程热更新
+// 每次 HTTP 请求、文件上传以及每次网关连接/重连前都会调用 provider 获取最新 token，
+// 返回的 token 可带或不带类型前缀；设置后 NewClient 的 token 参数可以为空。
+// provider 可能被并发调用，需自行缓存，避免每次请求都访问密钥管理系统
+func WithTokenProvider(provider func(ctx context.Context) (string, error)) ClientOption {
+	return func(c *Client) {
+		c.tokenFunc = provider
 	}
 }
 
@@ -94,15 +164,58 @@ func WithBaseURL(baseURL string) ClientOption {
 	}
 }
 
-// WithLogger 设置自定义日志器
-func WithLogger(logger *logrus.Logger) ClientOption {
+// WithUserAgent 设置所有请求（包括重试、文件上传与网关握手）的 User-Agent，便于 KOOK 识别机器人
+// 建议保留库标识，例如 "my-bot/1.2 " + kook.UserAgent；传入空字符串时保留默认值
+func WithUserAgent(userAgent string) ClientOption {
 	return func(c *Client) {
-		c.logger = logger
+		if userAgent != "" {
+			c.userAgent = userAgent
+		}
+	}
+}
+
+// WithDefaultTimeout 设置API请求（包括文件上传）的默认超时（包含重试），仅在传入的 ctx 没有 deadline 时生效
+// 超时后返回的错误满足 errors.Is(err, context.DeadlineExceeded)；0 表示不设置（默认）
+func WithDefaultTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.defaultTimeout = d
+	}
+}
+
+// WithLogger 设置自定义日志器，默认使用基于 slog 的日志器（Info 级别，输出到标准错误）
+// 可通过 NewSlogLogger 接入自定义的 slog.Logger，或通过 NopLogger 关闭日志；
+// 旧版传入 *logrus.Logger 的调用改为 WithLogger(NewLogrusLogger(logger))
+func WithLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		if logger != nil {
+			c.logger = logger
+		}
+	}
+}
+
+// WithBucketRateLimiter 开启或关闭基于响应头（X-Rate-Limit-*）的 bucket 限流，默认开启
+func WithBucketRateLimiter(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.bucketLimiterEnabled = enabled
 	}
 }
 
 // WithRateLimiter 设置自定义速率限制器
+//
+// Deprecated: 使用 WithGlobalRateLimiter
 func WithRateLimiter(rateLimiter *GlobalRateLimiter) ClientOption {
+	return WithGlobalRateLimiter(rateLimiter)
+}
+
+// WithRateLimitCallback 设置因限流而阻塞等待时的回调
+func WithRateLimitCallback(fn RateLimitWaitFunc) ClientOption {
+	return func(c *Client) {
+		c.onRateLimitWait = fn
+	}
+}
+
+// WithGlobalRateLimiter 设置自定义的本地令牌桶速率限制器
+func WithGlobalRateLimiter(rateLimiter *GlobalRateLimiter) ClientOption {
 	return func(c *Client) {
 		c.rateLimiter = rateLimiter
 	}
@@ -112,6 +225,7 @@ func WithRateLimiter(rateLimiter *GlobalRateLimiter) ClientOption {
 func WithoutRateLimit() ClientOption {
 	return func(c *Client) {
 		c.rateLimiter = nil
+		c.bucketLimiterEnabled = false
 	}
 }
 
@@ -122,6 +236,19 @@ func WithRetryConfig(config *RetryConfig) ClientOption {
 	}
 }
 
+// WithRetry 设置最大重试次数与初始退避延迟
+// 429 按服务端给出的 Retry-After / X-Rate-Limit-Reset 等待，5xx 按指数退避
+func WithRetry(maxRetries int, baseDelay time.Duration) ClientOption {
+	return func(c *Client) {
+		config := DefaultRetryConfig()
+		config.MaxRetries = maxRetries
+		if baseDelay > 0 {
+			config.InitialDelay = baseDelay
+		}
+		c.retryConfig = config
+	}
+}
+
 // WithoutRetry 禁用重试
 func WithoutRetry() ClientOption {
 	return func(c *Client) {
@@ -129,45 +256,104 @@ func WithoutRetry() ClientOption {
 	}
 }
 
-// NewClient 创建新的KOOK客户端
-func NewClient(token string, options ...ClientOption) *Client {
-	if token == "" {
-		panic("token不能为空")
+// WithBulkConcurrency 设置批量操作（如批量删除消息）的并发度
+func WithBulkConcurrency(n int) ClientOption {
+	return func(c *Client) {
+		if n > 0 {
+			c.bulkConcurrency = n
+		}
+	}
+}
+
+// WithIdempotency 开启发送消息的本地去重
+// 相同 nonce 的重复发送在 ttl 内直接返回首次发送的结果，ttl <= 0 时使用 DefaultIdempotencyTTL
+func WithIdempotency(ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		c.idempotency = true
+		c.idempotencyTTL = ttl
+	}
+}
+
+// WithIdempotencyTTL 开启发送消息的本地去重并设置每个 nonce 的缓存时长，d <= 0 时使用 DefaultIdempotencyTTL
+func WithIdempotencyTTL(d time.Duration) ClientOption {
+	return WithIdempotency(d)
+}
+
+// WithIdempotencyMaxEntries 开启发送消息的本地去重并限制缓存的 nonce 条数
+// 超过上限时淘汰最早缓存的结果，n <= 0 时使用 DefaultIdempotencyMaxEntries
+func WithIdempotencyMaxEntries(n int) ClientOption {
+	return func(c *Client) {
+		c.idempotency = true
+		c.idempotencyMaxLen = n
+	}
+}
+
+// WithEmojiShortcodes 发送文本和 KMarkdown 消息前把内容中的 :smile: 等 shortcode 替换为 unicode emoji
+// 未知的 shortcode 以及代码块中的文本保持原样，卡片和模板消息不做处理
+func WithEmojiShortcodes() ClientOption {
+	return func(c *Client) {
+		c.emojiShortcodes = true
 	}
+}
+
+// WithIgnoreSelf 设置 WebSocket 与 Webhook 是否丢弃机器人自己发出的消息事件，避免回复自身形成回环
+// 自身ID来自 user/me 的缓存，获取失败时记录警告且不过滤，稍后收到消息时再重试
+func WithIgnoreSelf(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.ignoreSelf = enabled
+	}
+}
 
+// NewClient 创建新的KOOK客户端，token 为空时必须通过 WithTokenProvider 提供
+func NewClient(token string, options ...ClientOption) *Client {
 	// 默认HTTP客户端
 	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
+		Timeout: DefaultHTTPTimeout,
 	}
 
-	// 默认日志器
-	logger := logrus.New()
-	logger.SetLevel(logrus.InfoLevel)
-	logger.SetFormatter(&logrus.TextFormatter{
-		TimestampFormat: "2006-01-02 15:04:05",
-		FullTimestamp:   true,
-	})
-
 	client := &Client{
 		httpClient:  httpClient,
 		token:       token,
-		tokenType:   TokenTypeBot,
+		tokenType:   TokenBot,
 		baseURL:     BaseURL,
-		logger:      logger,
+		userAgent:   UserAgent,
+		logger:      newDefaultLogger(),
 		rateLimiter: NewGlobalRateLimiter(),
 		retryConfig: DefaultRetryConfig(),
+
+		bucketLimiterEnabled: true,
+		bulkConcurrency:      5,
+		metrics:              noopMetrics{},
 	}
 
 	// 应用选项
 	for _, option := range options {
 		option(client)
 	}
+	if client.token == "" && client.tokenFunc == nil {
+		panic("token不能为空")
+	}
+
+	if client.idempotency {
+		client.nonceCache = newNonceCache(client.idempotencyTTL, client.idempotencyMaxLen)
+	}
+
+	if client.bucketLimiterEnabled {
+		onWait := client.onRateLimitWait
+		client.bucketLimiter = NewBucketRateLimiter(func(bucket string, wait time.Duration) {
+			client.metrics.IncRateLimited(bucket)
+			if onWait != nil {
+				onWait(bucket, wait)
+			}
+		})
+	}
 
 	// 初始化API服务
 	client.User = &UserService{client: client}
 	client.Guild = &GuildService{client: client}
 	client.Channel = &ChannelService{client: client}
 	client.Message = &MessageService{client: client}
+	client.DirectMessage = &DirectMessageService{client: client}
 	client.Gateway = &GatewayService{client: client}
 	client.Role = &RoleService{client: client}
 	client.Game = &GameService{client: client}
@@ -178,6 +364,7 @@ func NewClient(token string, options ...ClientOption) *Client {
 	client.Badge = &BadgeService{client: client}
 	client.Blacklist = &BlacklistService{client: client}
 	client.Emoji = &EmojiService{client: client}
+	client.GuildEmoji = &GuildEmojiService{client: client}
 	client.Region = &RegionService{client: client}
 	client.OAuth = &OAuthService{client: client}
 	client.Live = &LiveService{client: client}
@@ -188,29 +375,215 @@ func NewClient(token string, options ...ClientOption) *Client {
 	client.Order = &OrderService{client: client}
 	client.Coupon = &CouponService{client: client}
 	client.Boost = &BoostService{client: client}
+	client.Template = &TemplateService{client: client}
 
 	return client
 }
 
+// Connect 创建网关连接并完成首次连接，全过程受 ctx 控制，失败时返回错误且不在后台重试
+// 收到 hello 建立会话后返回的连接进入断线自动重连模式，事件处理器可在返回后注册，
+// 也可以通过 WithGatewayRouter 传入预先注册好处理器的路由器，避免错过连接后立即到达的事件
+// 默认请求压缩数据，可用 WithGatewayCompression(false) 关闭
+func (c *Client) Connect(ctx context.Context, opts ...WebSocketOption) (*WebSocketClient, error) {
+	if c.closed.Load() {
+		return nil, ErrClientClosed
+	}
+	ws := NewWebSocketClient(c, true, opts...)
+	if err := ws.ConnectContext(ctx); err != nil {
+		ws.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// RateLimitStatus 返回各 bucket 限流配额的快照，可用于配额不足时提前预警
+// 未启用 bucket 限流器或尚未收到带限流头的响应时返回空 map
+func (c *Client) RateLimitStatus() map[string]BucketStatus {
+	if c.bucketLimiter == nil {
+		return map[string]BucketStatus{}
+	}
+	return c.bucketLimiter.Status()
+}
+
+// ErrClientClosed 客户端已关闭
+var ErrClientClosed = errors.New("客户端已关闭")
+
+// WebSocket 返回最近创建的网关连接，尚未创建时按默认配置创建一个（不会立即连接）
+// 事件处理器注册在返回的连接上，随后调用 Start 建立连接；默认连接请求压缩数据
+func (c *Client) WebSocket() *WebSocketClient {
+	c.gatewaysMu.Lock()
+	defer c.gatewaysMu.Unlock()
+	if ws := c.gateway.Load(); ws != nil {
+		return ws
+	}
+	ws := newWebSocketClient(c, true)
+	c.trackGatewayLocked(ws)
+	return ws
+}
+
+// Start 启动网关连接与心跳，首次连接受 ctx 控制，收到 hello 后断线自动重连
+// 网关已在连接或运行中时直接返回；Close 之后调用返回 ErrClientClosed
+func (c *Client) Start(ctx context.Context) error {
+	if c.closed.Load() {
+		return ErrClientClosed
+	}
+	ws := c.WebSocket()
+	if ws.ConnectionState() != ConnectionStateClosed {
+		return nil
+	}
+	return ws.ConnectContext(ctx)
+}
+
+// Close 关闭所有网关连接并等待读循环、重连与心跳协程退出
+// REST 调用不受影响，可以继续使用；重复调用返回 ErrClientClosed
+func (c *Client) Close() error {
+	if !c.closed.CompareAndSwap(false, true) {
+		return ErrClientClosed
+	}
+
+	var errs []error
+	for _, ws := range c.trackedGateways() {
+		if err := ws.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// trackGateway 记录新创建的网关连接，并作为 WebSocket 与 ConnectionState 的默认连接
+func (c *Client) trackGateway(ws *WebSocketClient) {
+	c.gatewaysMu.Lock()
+	defer c.gatewaysMu.Unlock()
+	c.trackGatewayLocked(ws)
+}
+
+func (c *Client) trackGatewayLocked(ws *WebSocketClient) {
+	if c.gateways == nil {
+		c.gateways = make(map[*WebSocketClient]struct{})
+	}
+	c.gateways[ws] = struct{}{}
+	c.gateway.Store(ws)
+}
+
+// trackedGateways 返回当前由 Client 管理的全部网关连接
+func (c *Client) trackedGateways() []*WebSocketClient {
+	c.gatewaysMu.Lock()
+	defer c.gatewaysMu.Unlock()
+	gateways := make([]*WebSocketClient, 0, len(c.gateways))
+	for ws := range c.gateways {
+		gateways = append(gateways, ws)
+	}
+	return gateways
+}
+
+// untrackGateway 网关连接关闭后不再由 Client.Close 管理
+func (c *Client) untrackGateway(ws *WebSocketClient) {
+	c.gatewaysMu.Lock()
+	defer c.gatewaysMu.Unlock()
+	delete(c.gateways, ws)
+}
+
+// ConnectionState 返回最近创建的网关连接的状态，未创建网关时为 ConnectionStateClosed
+func (c *Client) ConnectionState() ConnectionState {
+	if ws := c.gateway.Load(); ws != nil {
+		return ws.ConnectionState()
+	}
+	return ConnectionStateClosed
+}
+
+// authorization 生成 Authorization 头，token 已带有类型前缀时不再重复添加
+// 设置了 WithTokenProvider 时每次调用都重新获取 token
+func (c *Client) authorization(ctx context.Context) (string, error) {
+	token := c.token
+	if c.tokenFunc != nil {
+		var err error
+		if token, err = c.tokenFunc(ctx); err != nil {
+			return "", fmt.Errorf("获取token失败: %w", err)
+		}
+		if token == "" {
+			return "", fmt.Errorf("获取token失败: token为空")
+		}
+	}
+
+	prefix := string(c.tokenType) + " "
+	if strings.HasPrefix(token, prefix) {
+		return token, nil
+	}
+	return prefix + token, nil
+}
+
 // buildURL 构建完整的API URL
 func (c *Client) buildURL(endpoint string) string {
 	endpoint = strings.TrimPrefix(endpoint, "/")
 	return fmt.Sprintf("%s/%s/%s", c.baseURL, Version, endpoint)
 }
 
-// doRequest 执行HTTP请求
-func (c *Client) doRequest(ctx context.Context, method, endpoint string, params map[string]interface{}, query map[string]string) (*Response, error) {
+// waitRateLimit 在发送请求前等待本地限流与 bucket 限流
+func (c *Client) waitRateLimit(ctx context.Context, endpoint string) error {
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.WaitContext(ctx, endpoint); err != nil {
+			return err
+		}
+	}
+	if c.bucketLimiter != nil {
+		return c.bucketLimiter.Wait(ctx, endpoint)
+	}
+	return nil
+}
+
+// updateRateLimit 根据响应头更新 bucket 限流状态
+func (c *Client) updateRateLimit(endpoint string, header http.Header) {
+	if c.bucketLimiter != nil {
+		c.bucketLimiter.Update(endpoint, header)
+	}
+}
+
+// doRequest 执行HTTP请求，params 不为 nil 时序列化为 JSON 请求体
+func (c *Client) doRequest(ctx context.Context, method, endpoint string, params interface{}, query map[string]string) (*Response, error) {
+	var body requestBody
+	if params != nil {
+		jsonData, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("序列化请求参数失败: %w", err)
+		}
+		c.logger.Debugf("请求参数: %s", jsonData)
+		body = func() (io.Reader, string, error) {
+			return bytes.NewReader(jsonData), "application/json", nil
+		}
+	}
+	return c.send(ctx, method, endpoint, query, body, c.retryConfig)
+}
+
+// requestBody 构造请求体及其 Content-Type；重试时会再次调用，每次都要返回完整的请求体
+type requestBody func() (io.Reader, string, error)
+
+// send 按重试策略发送请求，所有API请求（包括文件上传）共用的入口
+func (c *Client) send(ctx context.Context, method, endpoint string, query map[string]string, body requestBody, retryConfig *RetryConfig) (*Response, error) {
+	ctx, cancel := c.withDefaultTimeout(ctx)
+	defer cancel()
+
 	// 使用重试机制执行请求
 	return DoWithRetry(ctx, func(ctx context.Context) (*Response, error) {
-		return c.doSingleRequest(ctx, method, endpoint, params, query)
-	}, c.retryConfig, c.logger)
+		return c.doSingleRequest(ctx, method, endpoint, query, body)
+	}, retryConfig, c.logger)
+}
+
+// withDefaultTimeout 在 ctx 没有 deadline 时套上默认超时，用户显式设置的 deadline 优先
+func (c *Client) withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.defaultTimeout <= 0 {
+		return ctx, func() {}
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.defaultTimeout)
 }
 
 // doSingleRequest 执行单次HTTP请求
-func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, params map[string]interface{}, query map[string]string) (*Response, error) {
+func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, query map[string]string, newBody requestBody) (*Response, error) {
 	// 应用速率限制
-	if c.rateLimiter != nil {
-		c.rateLimiter.Wait(endpoint)
+	if err := c.waitRateLimit(ctx, endpoint); err != nil {
+		return nil, err
 	}
 
 	requestURL := c.buildURL(endpoint)
@@ -231,13 +604,16 @@ func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, p
 	}
 
 	var body io.Reader
-	if params != nil {
-		jsonData, err := json.Marshal(params)
-		if err != nil {
-			return nil, fmt.Errorf("序列化请求参数失败: %w", err)
+	var contentType string
+	if newBody != nil {
+		var err error
+		if body, contentType, err = newBody(); err != nil {
+			return nil, err
+		}
+		// 流式请求体（如上传表单的管道）在请求未发出时也要关闭，以结束写入协程
+		if closer, ok := body.(io.Closer); ok {
+			defer closer.Close()
 		}
-		body = bytes.NewBuffer(jsonData)
-		c.logger.WithField("params", string(jsonData)).Debugf("请求参数")
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, requestURL, body)
@@ -246,26 +622,39 @@ func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, p
 	}
 
 	// 设置请求头
-	req.Header.Set("Authorization", fmt.Sprintf("%s %s", c.tokenType, c.token))
-	req.Header.Set("User-Agent", UserAgent)
-	if method == "POST" && params != nil {
-		req.Header.Set("Content-Type", "application/json")
+	auth, err := c.authorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", auth)
+	req.Header.Set("User-Agent", c.userAgent)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 	req.Header.Set("Accept-Language", "zh-cn")
 
-	c.logger.WithFields(logrus.Fields{
-		"method":  method,
-		"url":     requestURL,
-		"headers": req.Header,
-	}).Debugf("发送API请求")
+	if err := c.interceptRequest(req); err != nil {
+		return nil, err
+	}
+
+	c.logger.Debugf("发送API请求: %s %s", method, requestURL)
 
 	// 执行请求
+	start := time.Now()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.metrics.ObserveRequest(endpoint, 0, time.Since(start))
 		c.logger.WithError(err).Errorf("请求失败")
 		return nil, fmt.Errorf("请求失败: %w", err)
 	}
-	defer resp.Body.Close()
+	defer drainAndClose(resp.Body)
+	c.metrics.ObserveRequest(endpoint, resp.StatusCode, time.Since(start))
+
+	c.updateRateLimit(endpoint, resp.Header)
+
+	if err := c.interceptResponse(resp); err != nil {
+		return nil, err
+	}
 
 	// 读取响应
 	respBody, err := io.ReadAll(resp.Body)
@@ -274,16 +663,28 @@ func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, p
 		return nil, fmt.Errorf("读取响应失败: %w", err)
 	}
 
-	c.logger.WithFields(logrus.Fields{
-		"status": resp.StatusCode,
-		"body":   string(respBody),
-	}).Debugf("收到API响应")
+	c.logger.Debugf("收到API响应: status=%d, body=%s", resp.StatusCode, respBody)
 
 	// 解析响应
 	var response Response
-	if err := json.Unmarshal(respBody, &response); err != nil {
-		c.logger.WithError(err).Errorf("解析响应失败")
-		return nil, fmt.Errorf("解析响应失败: %w", err)
+	parseErr := json.Unmarshal(respBody, &response)
+
+	// HTTP 层错误（如网关返回的 429/5xx 非标准响应体）
+	if resp.StatusCode >= http.StatusBadRequest && (parseErr != nil || response.Code == 0) {
+		err := NewKOOKErrorFromResponse(resp, respBody).WithContext(method, endpoint)
+		if err.RetryAfter == 0 {
+			err.RetryAfter = rateLimitResetDelay(resp.Header)
+		}
+		if err.IsRateLimited() {
+			c.metrics.IncRateLimited(rateLimitBucketName(endpoint, resp.Header))
+		}
+		c.logger.WithError(err).Errorf("API返回HTTP错误")
+		return nil, err
+	}
+
+	if parseErr != nil {
+		c.logger.WithError(parseErr).Errorf("解析响应失败")
+		return nil, fmt.Errorf("解析响应失败: %w", parseErr)
 	}
 
 	// 检查API错误
@@ -297,13 +698,16 @@ func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, p
 		}
 
 		// 从响应头中提取重试延迟
-		if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
-			if seconds, parseErr := time.ParseDuration(retryAfter + "s"); parseErr == nil {
-				err = err.WithRetryAfter(seconds)
-			}
+		if retryAfter := ExtractRetryAfter(resp); retryAfter > 0 {
+			err = err.WithRetryAfter(retryAfter)
+		} else if reset := rateLimitResetDelay(resp.Header); reset > 0 && err.IsRateLimited() {
+			err = err.WithRetryAfter(reset)
 		}
 
 		err.HTTPStatus = resp.StatusCode
+		if err.IsRateLimited() {
+			c.metrics.IncRateLimited(rateLimitBucketName(endpoint, resp.Header))
+		}
 
 		c.logger.WithError(err).Errorf("API返回错误")
 		return &response, err
@@ -313,6 +717,24 @@ func (c *Client) doSingleRequest(ctx context.Context, method, endpoint string, p
 	return &response, nil
 }
 
+// streamingHTTPClient 返回不设整体超时的 HTTP 客户端，与 httpClient 共用 Transport、Cookie 与重定向策略
+// http.Client.Timeout 也覆盖读取响应体的时间，会截断大文件下载，因此流式下载改由调用方的 ctx 控制超时
+func (c *Client) streamingHTTPClient() *http.Client {
+	hc := *c.httpClient
+	hc.Timeout = 0
+	return &hc
+}
+
+// maxDrainBytes 关闭响应前最多丢弃的剩余字节数，超过时放弃复用该连接
+const maxDrainBytes = 64 << 10
+
+// drainAndClose 读完剩余的响应体再关闭，保证底层连接可以放回连接池复用
+// 拦截器报错等提前返回的路径不会读取响应体，直接 Close 会导致连接被丢弃
+func drainAndClose(body io.ReadCloser) {
+	_, _ = io.CopyN(io.Discard, body, maxDrainBytes)
+	_ = body.Close()
+}
+
 // Get 发送GET请求
 func (c *Client) Get(ctx context.Context, endpoint string, query map[string]string) (*Response, error) {
 	return c.doRequest(ctx, "GET", endpoint, nil, query)
@@ -320,17 +742,39 @@ func (c *Client) Get(ctx context.Context, endpoint string, query map[string]stri
 
 // Post 发送POST请求
 func (c *Client) Post(ctx context.Context, endpoint string, params map[string]interface{}) (*Response, error) {
-	return c.doRequest(ctx, "POST", endpoint, params, nil)
+	return c.doRequest(ctx, "POST", endpoint, mapBody(params), nil)
 }
 
 // Put 发送PUT请求
 func (c *Client) Put(ctx context.Context, endpoint string, params map[string]interface{}) (*Response, error) {
-	return c.doRequest(ctx, "PUT", endpoint, params, nil)
+	return c.doRequest(ctx, "PUT", endpoint, mapBody(params), nil)
 }
 
 // Delete 发送DELETE请求
 func (c *Client) Delete(ctx context.Context, endpoint string, params map[string]interface{}) (*Response, error) {
-	return c.doRequest(ctx, "DELETE", endpoint, params, nil)
+	return c.doRequest(ctx, "DELETE", endpoint, mapBody(params), nil)
+}
+
+// Request 发送任意API请求，用于调用 SDK 尚未封装的接口
+// 与 Get/Post 共用鉴权、限流、重试与错误解析：code 不为 0 时返回 *KOOKError；
+// endpoint 为 v3 之后的路径（如 "guild/list"），body 会序列化为 JSON，为 nil 时不发送请求体
+func (c *Client) Request(ctx context.Context, method, endpoint string, query map[string]string, body interface{}) (*Response, error) {
+	method = strings.ToUpper(strings.TrimSpace(method))
+	if method == "" {
+		return nil, fmt.Errorf("请求方法不能为空")
+	}
+	if strings.TrimPrefix(endpoint, "/") == "" {
+		return nil, fmt.Errorf("接口路径不能为空")
+	}
+	return c.doRequest(ctx, method, endpoint, body, query)
+}
+
+// mapBody 把 nil map 转换为 nil 接口，避免发送 "null" 请求体
+func mapBody(params map[string]interface{}) interface{} {
+	if params == nil {
+		return nil
+	}
+	return params
 }
 
 // Response API响应结构
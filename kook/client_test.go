@@ -0,0 +1,43 @@
+package kook
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDrainAndCloseReusesConnection 提前返回（响应拦截器报错）的请求也读完响应体，连接回到连接池复用
+// 较新的 Go 版本在 Close 时也会自行丢弃少量剩余数据，该测试在 go.mod 声明的最低版本上才能区分是否 drain
+func TestDrainAndCloseReusesConnection(t *testing.T) {
+	var accepted atomic.Int32
+	body := `{"code":0,"message":"","data":"` + strings.Repeat("a", 16<<10) + `"}`
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			accepted.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	errRejected := errors.New("拒绝响应")
+	client := NewClient("token", WithBaseURL(server.URL), WithLogger(NopLogger()), WithoutRateLimit(),
+		WithRetry(0, 0),
+		WithResponseInterceptor(func(*http.Response) error { return errRejected }))
+
+	for i := 0; i < 5; i++ {
+		_, err := client.Get(context.Background(), "guild/list", nil)
+		require.ErrorIs(t, err, errRejected)
+	}
+	assert.Equal(t, int32(1), accepted.Load(), "5 次请求应复用同一条连接")
+}
@@ -0,0 +1,291 @@
+package kook
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// CommandHandler 命令处理函数
+type CommandHandler func(ctx context.Context, c *CommandContext) error
+
+// CommandContext 命令上下文
+type CommandContext struct {
+	Client  *Client
+	Event   *Event
+	Extra   *EventExtra // 消息事件的 extra，包含作者、服务器等信息
+	Command string      // 匹配到的命令，如 "/role add"；fallback 时为空
+	Args    []string    // 命令之后的参数，支持引号包裹
+	RawArgs string      // 命令之后未拆分的原始参数文本
+
+	ctx context.Context // 事件处理器的上下文，Reply 等方法继承其取消与追踪信息
+}
+
+// Reply 以引用原消息的方式回复 KMarkdown 消息，私聊消息会回复到私聊
+func (c *CommandContext) Reply(content string) (*Message, error) {
+	return c.reply(content, MessageTypeKMD)
+}
+
+// ReplyCard 以引用原消息的方式回复卡片消息
+func (c *CommandContext) ReplyCard(cards ...*Card) (*Message, error) {
+	content, err := NewCardMessage(cards...).JSON()
+	if err != nil {
+		return nil, err
+	}
+	return c.reply(content, MessageTypeCard)
+}
+
+// reply 向事件来源发送消息
+func (c *CommandContext) reply(content string, msgType int) (*Message, error) {
+	params := SendMessageParams{
+		TargetID: c.Event.TargetID,
+		Content:  content,
+		MsgType:  msgType,
+		Quote:    c.Event.MsgID,
+	}
+	if c.Event.ChannelType == "PERSON" {
+		params.Type = "private"
+		params.TargetID = c.Event.AuthorID
+	}
+	return c.Client.Message.SendMessage(c.context(), params)
+}
+
+// context 返回命令所属事件的上下文
+func (c *CommandContext) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// CommandRouter 轻量命令路由器
+// 通过 Register 注册到文字与 KMarkdown 消息事件，按注册的命令前缀匹配消息，
+// 多个命令同时匹配时取最长的一个，因此 "/role add" 可以作为 "/role" 的子命令。
+// 消息以 @机器人 开头时会先去掉该提及再匹配；机器人发送的消息会被忽略
+type CommandRouter struct {
+	client *Client
+
+	mu       sync.RWMutex
+	commands map[string]CommandHandler
+	names    []string // 按长度降序排列的命令
+	fallback CommandHandler
+	onError  func(c *CommandContext, err error)
+}
+
+// NewCommandRouter 创建命令路由器
+func NewCommandRouter(client *Client) *CommandRouter {
+	return &CommandRouter{
+		client:   client,
+		commands: make(map[string]CommandHandler),
+	}
+}
+
+// Handle 注册命令，prefix 为完整的命令前缀，如 "/ping"、"!role add"
+func (r *CommandRouter) Handle(prefix string, fn CommandHandler) {
+	name := strings.Join(strings.Fields(prefix), " ")
+	if name == "" || fn == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.commands[name]; !exists {
+		r.names = append(r.names, name)
+		sort.SliceStable(r.names, func(i, j int) bool {
+			return len(r.names[i]) > len(r.names[j])
+		})
+	}
+	r.commands[name] = fn
+}
+
+// Fallback 设置未匹配任何命令时的处理函数，此时 Args 为拆分后的整条消息
+func (r *CommandRouter) Fallback(fn CommandHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = fn
+}
+
+// OnError 设置命令处理函数返回错误时的回调，默认记录错误日志
+func (r *CommandRouter) OnError(fn func(c *CommandContext, err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onError = fn
+}
+
+// Register 把路由器注册到文字与 KMarkdown 消息事件，返回的函数用于注销
+func (r *CommandRouter) Register(router *EventRouter) func() {
+	offText := router.OnEventCtx(EventTypeTextMessage, r.HandleEventContext)
+	offKMD := router.OnEventCtx(EventTypeKMDMessage, r.HandleEventContext)
+	return func() {
+		offText()
+		offKMD()
+	}
+}
+
+// HandleEvent 处理消息事件，可直接作为 EventHandler 使用
+func (r *CommandRouter) HandleEvent(event *Event) {
+	r.HandleEventContext(context.Background(), event)
+}
+
+// HandleEventContext 以指定上下文处理消息事件，可直接作为 EventHandlerCtx 使用
+func (r *CommandRouter) HandleEventContext(ctx context.Context, event *Event) {
+	extra, err := event.ParseExtra()
+	if err != nil {
+		r.client.logger.Errorf("解析消息事件失败: %v", err)
+		return
+	}
+	if extra.Author.Bot {
+		return
+	}
+
+	text := r.commandText(ctx, event)
+
+	r.mu.RLock()
+	var (
+		name    string
+		handler CommandHandler
+	)
+	for _, candidate := range r.names {
+		if matchCommand(text, candidate) {
+			name, handler = candidate, r.commands[candidate]
+			break
+		}
+	}
+	if handler == nil {
+		handler = r.fallback
+	}
+	onError := r.onError
+	r.mu.RUnlock()
+
+	if handler == nil {
+		return
+	}
+
+	rawArgs := strings.TrimSpace(text[len(name):])
+	c := &CommandContext{
+		Client:  r.client,
+		Event:   event,
+		Extra:   extra,
+		Command: name,
+		Args:    SplitCommandArgs(rawArgs),
+		RawArgs: rawArgs,
+		ctx:     ctx,
+	}
+
+	if err := handler(ctx, c); err != nil {
+		if onError != nil {
+			onError(c, err)
+			return
+		}
+		r.client.logger.Errorf("处理命令 %q 失败: %v", name, err)
+	}
+}
+
+// commandText 把消息内容还原为用于匹配命令的文本，并去掉开头对机器人自身的提及
+func (r *CommandRouter) commandText(ctx context.Context, event *Event) string {
+	if event.Type != EventTypeKMDMessage {
+		text := strings.TrimSpace(event.Content)
+		if rest := strings.TrimPrefix(text, "(met)"); rest != text {
+			if userID, ok := closedTag(rest, "(met)"); ok {
+				if self, err := r.client.User.IsSelf(ctx, userID); err == nil && self {
+					return strings.TrimSpace(rest[len(userID)+len("(met)"):])
+				}
+			}
+		}
+		return text
+	}
+
+	tokens, err := ParseKMarkdown(event.Content)
+	if err != nil {
+		return strings.TrimSpace(event.Content)
+	}
+
+	// 跳过开头的空白文本后，若第一个 token 是 @机器人 则去掉
+	for i, tok := range tokens {
+		if tok.Type == TokenText && strings.TrimSpace(tok.Text) == "" {
+			continue
+		}
+		if tok.Type == TokenMentionUser {
+			if self, err := r.client.User.IsSelf(ctx, tok.Value); err == nil && self {
+				tokens = tokens[i+1:]
+			}
+		}
+		break
+	}
+
+	var sb strings.Builder
+	for _, tok := range tokens {
+		switch tok.Type {
+		case TokenMentionUser:
+			sb.WriteString("(met)" + tok.Value + "(met)")
+		case TokenMentionRole:
+			sb.WriteString("(rol)" + tok.Value + "(rol)")
+		case TokenChannel:
+			sb.WriteString("(chn)" + tok.Value + "(chn)")
+		case TokenMentionAll:
+			sb.WriteString("(met)all(met)")
+		case TokenMentionHere:
+			sb.WriteString("(met)here(met)")
+		case TokenEmoji:
+			sb.WriteString("(emj)" + tok.Text + "(emj)[" + tok.Value + "]")
+		default:
+			sb.WriteString(tok.Text)
+		}
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// matchCommand 判断文本是否以命令开头，命令之后必须是结尾或空白
+func matchCommand(text, name string) bool {
+	if !strings.HasPrefix(text, name) {
+		return false
+	}
+	rest := text[len(name):]
+	return rest == "" || unicode.IsSpace([]rune(rest)[0])
+}
+
+// SplitCommandArgs 按空白拆分参数，支持双引号与单引号包裹，双引号内可用反斜杠转义
+func SplitCommandArgs(s string) []string {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+		escaped bool
+	)
+
+	for _, ch := range s {
+		switch {
+		case escaped:
+			current.WriteRune(ch)
+			escaped = false
+		case ch == '\\' && quote == '"':
+			escaped = true
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			} else {
+				current.WriteRune(ch)
+			}
+		case ch == '"' || ch == '\'':
+			quote = ch
+			inArg = true
+		case unicode.IsSpace(ch):
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(ch)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args
+}
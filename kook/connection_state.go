@@ -0,0 +1,107 @@
+package kook
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ConnectionState 网关连接状态
+type ConnectionState int
+
+// 网关连接状态常量
+const (
+	ConnectionStateClosed       ConnectionState = iota // 未连接或已关闭
+	ConnectionStateConnecting                          // 首次连接中
+	ConnectionStateConnected                           // 已连接（收到 hello 或 resume ack）
+	ConnectionStateReconnecting                        // 断线后重连中
+	ConnectionStateResuming                            // 正在恢复会话
+)
+
+// String 返回状态名称
+func (s ConnectionState) String() string {
+	switch s {
+	case ConnectionStateClosed:
+		return "closed"
+	case ConnectionStateConnecting:
+		return "connecting"
+	case ConnectionStateConnected:
+		return "connected"
+	case ConnectionStateReconnecting:
+		return "reconnecting"
+	case ConnectionStateResuming:
+		return "resuming"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// StateChangeHandler 连接状态变化回调
+type StateChangeHandler func(old, new ConnectionState)
+
+// connectionStateMachine 线程安全的连接状态
+// 状态变化按转移顺序排队，由单独的通知协程在不持有锁的情况下串行调用回调，
+// 回调中可以再次触发状态转移（如调用 Close、Connect），不会死锁
+type connectionStateMachine struct {
+	mu        sync.Mutex
+	state     ConnectionState
+	handlers  []StateChangeHandler
+	pending   []stateChange // 尚未通知的状态变化
+	notifying bool          // 通知协程是否在运行
+}
+
+// stateChange 一次待通知的状态变化，handlers 为转移发生时已注册的回调
+type stateChange struct {
+	old, new ConnectionState
+	handlers []StateChangeHandler
+}
+
+// Load 读取当前状态
+func (m *connectionStateMachine) Load() ConnectionState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.state
+}
+
+// OnChange 注册状态变化回调
+func (m *connectionStateMachine) OnChange(handler StateChangeHandler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, handler)
+}
+
+// Transition 切换到新状态，状态未变化时不触发回调
+// 回调在通知协程中异步执行，Transition 不等待回调返回
+func (m *connectionStateMachine) Transition(state ConnectionState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	old := m.state
+	m.state = state
+	if old == state || len(m.handlers) == 0 {
+		return
+	}
+	m.pending = append(m.pending, stateChange{old: old, new: state, handlers: m.handlers})
+	if !m.notifying {
+		m.notifying = true
+		go m.notify()
+	}
+}
+
+// notify 按顺序通知排队的状态变化，队列清空后退出
+func (m *connectionStateMachine) notify() {
+	for {
+		m.mu.Lock()
+		if len(m.pending) == 0 {
+			m.notifying = false
+			m.mu.Unlock()
+			return
+		}
+		change := m.pending[0]
+		m.pending = m.pending[1:]
+		m.mu.Unlock()
+
+		for _, handler := range change.handlers {
+			handler(change.old, change.new)
+		}
+	}
+}
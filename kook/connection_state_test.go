@@ -0,0 +1,40 @@
+package kook
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestStateTransitionFromHandler 回调中再次转移状态不会死锁，所有变化按顺序通知
+func TestStateTransitionFromHandler(t *testing.T) {
+	var m connectionStateMachine
+	var mu sync.Mutex
+	var changes []ConnectionState
+	done := make(chan struct{})
+	m.OnChange(func(old, new ConnectionState) {
+		mu.Lock()
+		changes = append(changes, new)
+		mu.Unlock()
+		switch new {
+		case ConnectionStateConnected:
+			m.Transition(ConnectionStateClosed)
+		case ConnectionStateClosed:
+			close(done)
+		}
+	})
+
+	m.Transition(ConnectionStateConnecting)
+	m.Transition(ConnectionStateConnected)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("在状态回调中转移状态死锁")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []ConnectionState{ConnectionStateConnecting, ConnectionStateConnected, ConnectionStateClosed}, changes)
+}
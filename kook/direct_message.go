@@ -0,0 +1,141 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// DirectMessageService 私聊会话相关API服务
+type DirectMessageService struct {
+	client *Client
+
+	codes sync.Map // 目标用户ID -> 私聊会话Code
+}
+
+// ChatSession 私聊会话
+type ChatSession struct {
+	Code            string `json:"code"`              // 私聊会话Code
+	LastReadTime    int64  `json:"last_read_time"`    // 上次阅读时间（毫秒）
+	LatestMsgTime   int64  `json:"latest_msg_time"`   // 最新消息时间（毫秒）
+	UnreadCount     int    `json:"unread_count"`      // 未读消息数
+	IsFriend        bool   `json:"is_friend"`         // 是否为好友
+	IsBlocked       bool   `json:"is_blocked"`        // 是否已屏蔽对方
+	IsTargetBlocked bool   `json:"is_target_blocked"` // 是否被对方屏蔽
+	TargetInfo      User   `json:"target_info"`       // 目标用户信息
+}
+
+// CreateSession 创建私聊会话并返回会话Code，已存在时返回已有会话
+func (s *DirectMessageService) CreateSession(ctx context.Context, targetID string) (string, error) {
+	if targetID == "" {
+		return "", fmt.Errorf("目标用户ID不能为空")
+	}
+
+	params := map[string]interface{}{
+		"target_id": targetID,
+	}
+
+	resp, err := s.client.Post(ctx, "user-chat/create", params)
+	if err != nil {
+		return "", err
+	}
+
+	var session ChatSession
+	if err := json.Unmarshal(resp.Data, &session); err != nil {
+		return "", fmt.Errorf("解析私聊会话失败: %w", err)
+	}
+	if session.Code == "" {
+		return "", fmt.Errorf("未返回私聊会话Code")
+	}
+
+	s.codes.Store(targetID, session.Code)
+	return session.Code, nil
+}
+
+// GetSession 获取私聊会话详情
+func (s *DirectMessageService) GetSession(ctx context.Context, chatCode string) (*ChatSession, error) {
+	if chatCode == "" {
+		return nil, fmt.Errorf("私聊会话Code不能为空")
+	}
+
+	resp, err := s.client.Get(ctx, "user-chat/view", map[string]string{"chat_code": chatCode})
+	if err != nil {
+		return nil, err
+	}
+
+	var session ChatSession
+	if err := json.Unmarshal(resp.Data, &session); err != nil {
+		return nil, fmt.Errorf("解析私聊会话失败: %w", err)
+	}
+
+	return &session, nil
+}
+
+// IterateSessions 创建私聊会话列表迭代器
+func (s *DirectMessageService) IterateSessions(ctx context.Context) *PageIterator[ChatSession] {
+	return newPageIterator[ChatSession](s.client, "user-chat/list", nil, 50)
+}
+
+// ListSessions 获取全部私聊会话（自动翻页）
+func (s *DirectMessageService) ListSessions(ctx context.Context) ([]ChatSession, error) {
+	return s.IterateSessions(ctx).All(ctx)
+}
+
+// DeleteSession 删除私聊会话
+func (s *DirectMessageService) DeleteSession(ctx context.Context, chatCode string) error {
+	if chatCode == "" {
+		return fmt.Errorf("私聊会话Code不能为空")
+	}
+
+	params := map[string]interface{}{
+		"chat_code": chatCode,
+	}
+
+	if _, err := s.client.Post(ctx, "user-chat/delete", params); err != nil {
+		return err
+	}
+
+	s.codes.Range(func(key, value interface{}) bool {
+		if value == chatCode {
+			s.codes.Delete(key)
+		}
+		return true
+	})
+	return nil
+}
+
+// GetMessagesBefore 获取私聊会话中指定消息之前的最多 limit 条消息，limit 为0时使用默认值50
+func (s *DirectMessageService) GetMessagesBefore(ctx context.Context, chatCode, msgID string, limit int) ([]Message, error) {
+	return s.getMessagesAt(ctx, chatCode, FlagBefore, msgID, limit)
+}
+
+// GetMessagesAfter 获取私聊会话中指定消息之后的最多 limit 条消息，limit 为0时使用默认值50
+func (s *DirectMessageService) GetMessagesAfter(ctx context.Context, chatCode, msgID string, limit int) ([]Message, error) {
+	return s.getMessagesAt(ctx, chatCode, FlagAfter, msgID, limit)
+}
+
+// GetMessagesAround 获取私聊会话中指定消息前后的最多 limit 条消息，limit 为0时使用默认值50
+func (s *DirectMessageService) GetMessagesAround(ctx context.Context, chatCode, msgID string, limit int) ([]Message, error) {
+	return s.getMessagesAt(ctx, chatCode, FlagAround, msgID, limit)
+}
+
+// getMessagesAt 按会话Code查询私聊消息
+func (s *DirectMessageService) getMessagesAt(ctx context.Context, chatCode string, flag MessageListFlag, msgID string, limit int) ([]Message, error) {
+	if chatCode == "" {
+		return nil, fmt.Errorf("私聊会话Code不能为空")
+	}
+	params := GetMessageListParams{
+		Type:     "private",
+		ChatCode: chatCode,
+	}
+	return s.client.Message.getMessagesAt(ctx, "", params, flag, msgID, limit)
+}
+
+// sessionCode 获取与目标用户的会话Code，优先使用缓存，没有时自动创建会话
+func (s *DirectMessageService) sessionCode(ctx context.Context, targetID string) (string, error) {
+	if code, ok := s.codes.Load(targetID); ok {
+		return code.(string), nil
+	}
+	return s.CreateSession(ctx, targetID)
+}
@@ -8,6 +8,8 @@ import (
 )
 
 // EmojiService 表情包相关API服务
+//
+// Deprecated: KOOK 没有 emoji/* 端点，服务器表情请使用 GuildEmojiService（guild-emoji/*）
 type EmojiService struct {
 	client *Client
 }
@@ -0,0 +1,276 @@
+package kook
+
+import (
+	"strings"
+)
+
+// emojiShortcodes 常见 emoji 的 shortcode 映射表（与 GitHub/Slack 的命名保持一致）
+var emojiShortcodes = map[string]string{
+	// 表情
+	"smile":                        "😄",
+	"smiley":                       "😃",
+	"grinning":                     "😀",
+	"grin":                         "😁",
+	"laughing":                     "😆",
+	"joy":                          "😂",
+	"rofl":                         "🤣",
+	"sweat_smile":                  "😅",
+	"wink":                         "😉",
+	"blush":                        "😊",
+	"innocent":                     "😇",
+	"slightly_smiling_face":        "🙂",
+	"upside_down_face":             "🙃",
+	"heart_eyes":                   "😍",
+	"star_struck":                  "🤩",
+	"kissing_heart":                "😘",
+	"yum":                          "😋",
+	"stuck_out_tongue":             "😛",
+	"stuck_out_tongue_winking_eye": "😜",
+	"thinking":                     "🤔",
+	"neutral_face":                 "😐",
+	"expressionless":               "😑",
+	"no_mouth":                     "😶",
+	"smirk":                        "😏",
+	"unamused":                     "😒",
+	"roll_eyes":                    "🙄",
+	"grimacing":                    "😬",
+	"relieved":                     "😌",
+	"pensive":                      "😔",
+	"sleepy":                       "😪",
+	"sleeping":                     "😴",
+	"mask":                         "😷",
+	"nerd_face":                    "🤓",
+	"sunglasses":                   "😎",
+	"confused":                     "😕",
+	"worried":                      "😟",
+	"open_mouth":                   "😮",
+	"astonished":                   "😲",
+	"flushed":                      "😳",
+	"pleading_face":                "🥺",
+	"cry":                          "😢",
+	"sob":                          "😭",
+	"scream":                       "😱",
+	"angry":                        "😠",
+	"rage":                         "😡",
+	"skull":                        "💀",
+	"poop":                         "💩",
+	"clown_face":                   "🤡",
+	"ghost":                        "👻",
+	"alien":                        "👽",
+	"robot":                        "🤖",
+	"see_no_evil":                  "🙈",
+	"hear_no_evil":                 "🙉",
+	"speak_no_evil":                "🙊",
+	"partying_face":                "🥳",
+	"hugs":                         "🤗",
+	"shushing_face":                "🤫",
+	"face_with_hand_over_mouth":    "🤭",
+	"yawning_face":                 "🥱",
+
+	// 手势
+	"+1":              "👍",
+	"thumbsup":        "👍",
+	"-1":              "👎",
+	"thumbsdown":      "👎",
+	"ok_hand":         "👌",
+	"v":               "✌️",
+	"crossed_fingers": "🤞",
+	"wave":            "👋",
+	"clap":            "👏",
+	"raised_hands":    "🙌",
+	"pray":            "🙏",
+	"handshake":       "🤝",
+	"muscle":          "💪",
+	"point_up":        "☝️",
+	"point_down":      "👇",
+	"point_left":      "👈",
+	"point_right":     "👉",
+	"fist":            "✊",
+	"punch":           "👊",
+	"raised_hand":     "✋",
+	"eyes":            "👀",
+
+	// 心形与符号
+	"heart":                    "❤️",
+	"orange_heart":             "🧡",
+	"yellow_heart":             "💛",
+	"green_heart":              "💚",
+	"blue_heart":               "💙",
+	"purple_heart":             "💜",
+	"black_heart":              "🖤",
+	"broken_heart":             "💔",
+	"sparkling_heart":          "💖",
+	"100":                      "💯",
+	"fire":                     "🔥",
+	"sparkles":                 "✨",
+	"star":                     "⭐",
+	"star2":                    "🌟",
+	"zap":                      "⚡",
+	"boom":                     "💥",
+	"tada":                     "🎉",
+	"confetti_ball":            "🎊",
+	"balloon":                  "🎈",
+	"gift":                     "🎁",
+	"trophy":                   "🏆",
+	"medal_sports":             "🏅",
+	"first_place_medal":        "🥇",
+	"crown":                    "👑",
+	"gem":                      "💎",
+	"bell":                     "🔔",
+	"mega":                     "📣",
+	"loudspeaker":              "📢",
+	"bulb":                     "💡",
+	"rocket":                   "🚀",
+	"warning":                  "⚠️",
+	"no_entry":                 "⛔",
+	"x":                        "❌",
+	"white_check_mark":         "✅",
+	"heavy_check_mark":         "✔️",
+	"question":                 "❓",
+	"exclamation":              "❗",
+	"bangbang":                 "‼️",
+	"red_circle":               "🔴",
+	"green_circle":             "🟢",
+	"large_blue_circle":        "🔵",
+	"arrow_up":                 "⬆️",
+	"arrow_down":               "⬇️",
+	"arrow_left":               "⬅️",
+	"arrow_right":              "➡️",
+	"lock":                     "🔒",
+	"unlock":                   "🔓",
+	"key":                      "🔑",
+	"link":                     "🔗",
+	"pushpin":                  "📌",
+	"memo":                     "📝",
+	"calendar":                 "📅",
+	"hourglass":                "⌛",
+	"alarm_clock":              "⏰",
+	"moneybag":                 "💰",
+	"chart_with_upwards_trend": "📈",
+	"speech_balloon":           "💬",
+	"zzz":                      "💤",
+
+	// 自然与食物
+	"sunny":            "☀️",
+	"cloud":            "☁️",
+	"umbrella":         "☔",
+	"snowflake":        "❄️",
+	"rainbow":          "🌈",
+	"crescent_moon":    "🌙",
+	"rose":             "🌹",
+	"cherry_blossom":   "🌸",
+	"four_leaf_clover": "🍀",
+	"dog":              "🐶",
+	"cat":              "🐱",
+	"panda_face":       "🐼",
+	"pig":              "🐷",
+	"penguin":          "🐧",
+	"apple":            "🍎",
+	"watermelon":       "🍉",
+	"pizza":            "🍕",
+	"hamburger":        "🍔",
+	"cake":             "🍰",
+	"birthday":         "🎂",
+	"coffee":           "☕",
+	"tea":              "🍵",
+	"beer":             "🍺",
+	"beers":            "🍻",
+
+	// 活动与物品
+	"video_game":   "🎮",
+	"game_die":     "🎲",
+	"dart":         "🎯",
+	"musical_note": "🎵",
+	"notes":        "🎶",
+	"headphones":   "🎧",
+	"microphone":   "🎤",
+	"soccer":       "⚽",
+	"basketball":   "🏀",
+	"computer":     "💻",
+	"iphone":       "📱",
+	"camera":       "📷",
+	"book":         "📖",
+	"envelope":     "✉️",
+	"hammer":       "🔨",
+	"wrench":       "🔧",
+	"gear":         "⚙️",
+	"shield":       "🛡️",
+}
+
+// EmojiFromShortcode 查找 shortcode 对应的 unicode emoji，code 可带或不带两侧的冒号
+func EmojiFromShortcode(code string) (string, bool) {
+	code = strings.TrimSuffix(strings.TrimPrefix(code, ":"), ":")
+	emoji, ok := emojiShortcodes[strings.ToLower(code)]
+	return emoji, ok
+}
+
+// ReplaceEmojiShortcodes 把内容中的 :shortcode: 替换为 unicode emoji，未知的 shortcode 保持原样
+// 反引号包裹的行内代码与 ``` 代码块中的文本不做替换
+func ReplaceEmojiShortcodes(content string) string {
+	if !strings.Contains(content, ":") {
+		return content
+	}
+
+	var b strings.Builder
+	b.Grow(len(content))
+	for len(content) > 0 {
+		// 找到下一段代码（``` 代码块优先于行内代码），之前的部分做替换，代码原样保留
+		idx := strings.IndexByte(content, '`')
+		if idx < 0 {
+			b.WriteString(replaceShortcodes(content))
+			break
+		}
+		b.WriteString(replaceShortcodes(content[:idx]))
+		content = content[idx:]
+
+		fence := "`"
+		if strings.HasPrefix(content, "```") {
+			fence = "```"
+		}
+		end := strings.Index(content[len(fence):], fence)
+		if end < 0 {
+			// 没有闭合的反引号，按普通文本处理剩余内容
+			b.WriteString(content[:len(fence)])
+			content = content[len(fence):]
+			continue
+		}
+		end += 2 * len(fence)
+		b.WriteString(content[:end])
+		content = content[end:]
+	}
+	return b.String()
+}
+
+// replaceShortcodes 替换不含代码的文本片段中的 shortcode
+// 逐个冒号尝试匹配，连续书写的 :a::b: 也能全部替换
+func replaceShortcodes(text string) string {
+	if !strings.Contains(text, ":") {
+		return text
+	}
+
+	var b strings.Builder
+	b.Grow(len(text))
+	for {
+		start := strings.IndexByte(text, ':')
+		if start < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		end := strings.IndexByte(text[start+1:], ':')
+		if end < 0 {
+			b.WriteString(text)
+			return b.String()
+		}
+		end += start + 1
+
+		if emoji, ok := emojiShortcodes[text[start+1:end]]; ok {
+			b.WriteString(text[:start])
+			b.WriteString(emoji)
+			text = text[end+1:]
+			continue
+		}
+		// 不是已知的 shortcode，结尾的冒号可能是下一个 shortcode 的开头
+		b.WriteString(text[:end])
+		text = text[end:]
+	}
+}
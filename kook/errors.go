@@ -2,6 +2,7 @@ package kook
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -41,6 +42,18 @@ var ErrorCodeMap = map[ErrorCode]string{
 	ErrorCodeGatewayTimeout:      "网关超时",
 }
 
+// 可与 errors.Is 配合使用的哨兵错误，KOOKError 会按错误码/HTTP状态匹配这些错误
+var (
+	// ErrRateLimited 请求被限流
+	ErrRateLimited = errors.New("请求过于频繁")
+	// ErrUnauthorized 认证失败
+	ErrUnauthorized = errors.New("认证失败")
+	// ErrForbidden 权限不足
+	ErrForbidden = errors.New("权限不足")
+	// ErrResourceNotFound 资源不存在
+	ErrResourceNotFound = errors.New("资源不存在")
+)
+
 // KOOKError KOOK API 错误
 type KOOKError struct {
 	Code       int           `json:"code"`
@@ -67,6 +80,21 @@ func (e *KOOKError) Error() string {
 	return fmt.Sprintf("KOOK API错误 [%d]: 未知错误", e.Code)
 }
 
+// Is 支持 errors.Is 匹配 ErrRateLimited、ErrUnauthorized、ErrForbidden 和 ErrResourceNotFound
+func (e *KOOKError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.IsRateLimited()
+	case ErrUnauthorized:
+		return e.IsAuthError()
+	case ErrForbidden:
+		return e.IsPermissionError()
+	case ErrResourceNotFound:
+		return e.IsNotFoundError()
+	}
+	return false
+}
+
 // IsRetryable 判断错误是否可重试
 func (e *KOOKError) IsRetryable() bool {
 	// KOOK 5xx 错误码通常是 50xxx
@@ -224,23 +252,26 @@ func NewValidationErrorWithValue(field, message, value string) *ValidationError
 	}
 }
 
-// IsKOOKError 检查是否为 KOOK 错误
+// IsKOOKError 检查是否为 KOOK 错误（包括被包装的错误）
 func IsKOOKError(err error) (*KOOKError, bool) {
-	if kookErr, ok := err.(*KOOKError); ok {
+	var kookErr *KOOKError
+	if errors.As(err, &kookErr) {
 		return kookErr, true
 	}
 	return nil, false
 }
 
-// IsValidationError 检查是否为验证错误
+// IsValidationError 检查是否为验证错误（包括被包装的错误）
 func IsValidationError(err error) (*ValidationError, bool) {
-	if validationErr, ok := err.(*ValidationError); ok {
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
 		return validationErr, true
 	}
 	return nil, false
 }
 
-// 为了保持向后兼容，保留原有的 APIError 类型
+// APIError API 返回非零 code 时的错误类型，与 KOOKError 为同一类型
+// 可通过 var apiErr *APIError; errors.As(err, &apiErr) 获取 Code、Message、HTTPStatus、Endpoint
 type APIError = KOOKError
 
 // IsAPIError 检查是否为 API 错误（向后兼容）
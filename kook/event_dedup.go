@@ -0,0 +1,96 @@
+package kook
+
+import (
+	"container/list"
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// 消息级事件幂等的默认参数
+const (
+	DefaultMessageDedupSize = 4096
+	DefaultMessageDedupTTL  = 10 * time.Minute
+)
+
+// msgDeduplicator 记录近期处理过的事件键，按 LRU 淘汰，超过 TTL 的记录视为未处理
+type msgDeduplicator struct {
+	mu    sync.Mutex
+	size  int
+	ttl   time.Duration
+	order *list.List // 最新的在队首
+	items map[string]*list.Element
+}
+
+// dedupEntry 去重记录
+type dedupEntry struct {
+	key       string
+	expiresAt time.Time
+}
+
+func newMsgDeduplicator(size int, ttl time.Duration) *msgDeduplicator {
+	if size <= 0 {
+		size = DefaultMessageDedupSize
+	}
+	if ttl <= 0 {
+		ttl = DefaultMessageDedupTTL
+	}
+	return &msgDeduplicator{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// Seen 判断键是否在 TTL 内处理过；未处理过时记录该键
+func (d *msgDeduplicator) Seen(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if el, ok := d.items[key]; ok {
+		entry := el.Value.(*dedupEntry)
+		if now.Before(entry.expiresAt) {
+			return true
+		}
+		entry.expiresAt = now.Add(d.ttl)
+		d.order.MoveToFront(el)
+		return false
+	}
+
+	d.items[key] = d.order.PushFront(&dedupEntry{key: key, expiresAt: now.Add(d.ttl)})
+	for d.order.Len() > d.size {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.items, oldest.Value.(*dedupEntry).key)
+	}
+	return false
+}
+
+// eventDedupKey 计算事件的幂等键，无法判断时返回空字符串
+// 每条事件的 msg_id 唯一，优先使用；缺失时回应事件退化为 子类型+消息ID+表情+用户 的组合键
+func eventDedupKey(event *Event) string {
+	if event.MsgID != "" {
+		return event.MsgID
+	}
+	if event.Type != MessageTypeSystem {
+		return ""
+	}
+
+	extra, err := event.ParseExtra()
+	if err != nil {
+		return ""
+	}
+	switch extra.Type {
+	case SystemEventAddedReaction, SystemEventDeletedReaction,
+		SystemEventPrivateAddedReaction, SystemEventPrivateDeletedReaction:
+	default:
+		return ""
+	}
+
+	var body ReactionEvent
+	if err := json.Unmarshal(extra.Body, &body); err != nil || body.MsgID == "" {
+		return ""
+	}
+	return extra.Type + ":" + body.MsgID + ":" + body.Emoji.ID + ":" + body.UserID
+}
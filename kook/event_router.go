@@ -0,0 +1,313 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// EventRouter 事件路由器，负责事件处理器的注册、注销与分发
+// WebhookHandler 与 WebSocketClient 都内嵌 EventRouter，因此两种接入方式的注册 API 完全一致；
+// 也可以创建一个路由器，通过 WithWebhookRouter / WithGatewayRouter 在两种接入方式之间共享同一套处理器
+type EventRouter struct {
+	logger Logger
+
+	mu       sync.RWMutex
+	handlers map[int][]registeredHandler
+	filters  []registeredFilter
+	nextID   uint64
+	inflight sync.WaitGroup // 异步分发中尚未结束的处理器
+	dedup    *msgDeduplicator
+}
+
+// 内部保留的处理器分组，不会与 KOOK 事件类型冲突
+const (
+	unhandledEventType = math.MinInt32     // OnUnhandled 注册的兜底处理器
+	anyEventType       = math.MinInt32 + 1 // OnAnyEvent 注册的 catch-all 处理器
+)
+
+// registeredHandler 带内部ID的事件处理器，用于注销时精确定位
+type registeredHandler struct {
+	id uint64
+	fn EventHandlerCtx
+}
+
+// EventFilter 事件过滤器，返回 false 的事件在分发前被丢弃
+type EventFilter func(event *Event) bool
+
+// registeredFilter 带内部ID的事件过滤器，用于移除时精确定位
+type registeredFilter struct {
+	id uint64
+	fn EventFilter
+}
+
+// NewEventRouter 创建事件路由器，logger 为 nil 时使用默认日志器
+func NewEventRouter(logger Logger) *EventRouter {
+	if logger == nil {
+		logger = newDefaultLogger()
+	}
+	return &EventRouter{
+		logger:   logger,
+		handlers: make(map[int][]registeredHandler),
+	}
+}
+
+// OnEvent 注册事件处理器，返回的函数用于注销该处理器（可重复调用）
+func (r *EventRouter) OnEvent(eventType int, handler EventHandler) func() {
+	return r.OnEventCtx(eventType, func(_ context.Context, event *Event) {
+		handler(event)
+	})
+}
+
+// OnEventCtx 注册接收上下文的事件处理器，处理器内发起的 API 调用可继承取消与追踪信息
+func (r *EventRouter) OnEventCtx(eventType int, handler EventHandlerCtx) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	id := r.nextID
+	r.handlers[eventType] = append(r.handlers[eventType], registeredHandler{id: id, fn: handler})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.removeHandler(eventType, id)
+		})
+	}
+}
+
+// OnEventErr 注册返回错误的事件处理器，返回的函数用于注销该处理器（可重复调用）
+// 处理器返回的错误记录到日志后继续调用后续处理器；配合 Webhook 的 WithSyncDispatch 可按序处理并汇报失败
+func (r *EventRouter) OnEventErr(eventType int, handler EventHandlerErr) func() {
+	return r.OnEventCtx(eventType, func(ctx context.Context, event *Event) {
+		if err := handler(ctx, event); err != nil {
+			r.logger.WithError(err).Errorf("事件处理器返回错误: 类型=%d, msg_id=%s", event.Type, event.MsgID)
+		}
+	})
+}
+
+// OnUnhandled 注册兜底处理器，只在事件类型没有任何处理器时调用，返回的函数用于注销（可重复调用）
+// 适合开发期打印未处理的事件，发现遗漏的事件类型；被过滤器丢弃的事件不会触发
+func (r *EventRouter) OnUnhandled(handler EventHandler) func() {
+	return r.OnEvent(unhandledEventType, handler)
+}
+
+// OnAnyEvent 注册 catch-all 处理器，每个事件（不论类型）都会调用，返回的函数用于注销（可重复调用）
+// catch-all 处理器与类型处理器并存，同步分发时先于类型处理器、按注册顺序调用，适合事件归档与审计；
+// 它不算作事件类型的处理器，不影响 OnUnhandled 的触发
+func (r *EventRouter) OnAnyEvent(handler EventHandler) func() {
+	return r.OnEvent(anyEventType, handler)
+}
+
+// removeHandler 按ID移除事件处理器
+func (r *EventRouter) removeHandler(eventType int, id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	handlers := r.handlers[eventType]
+	for i, h := range handlers {
+		if h.id != id {
+			continue
+		}
+		// 复制一份新切片，避免影响正在分发中的旧切片
+		updated := make([]registeredHandler, 0, len(handlers)-1)
+		updated = append(updated, handlers[:i]...)
+		updated = append(updated, handlers[i+1:]...)
+		if len(updated) == 0 {
+			delete(r.handlers, eventType)
+		} else {
+			r.handlers[eventType] = updated
+		}
+		return
+	}
+}
+
+// AddFilter 添加事件过滤器，返回的函数用于移除该过滤器（可重复调用）
+// 多个过滤器为 AND 关系：任一过滤器返回 false 的事件都不会分发给处理器。
+// 过滤器在接收事件的 goroutine 中同步执行，应避免阻塞
+func (r *EventRouter) AddFilter(filter EventFilter) func() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	id := r.nextID
+	// 复制一份新切片，避免影响正在分发中的旧切片
+	filters := make([]registeredFilter, 0, len(r.filters)+1)
+	filters = append(filters, r.filters...)
+	r.filters = append(filters, registeredFilter{id: id, fn: filter})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			r.removeFilter(id)
+		})
+	}
+}
+
+// removeFilter 按ID移除事件过滤器
+func (r *EventRouter) removeFilter(id uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, f := range r.filters {
+		if f.id != id {
+			continue
+		}
+		updated := make([]registeredFilter, 0, len(r.filters)-1)
+		updated = append(updated, r.filters[:i]...)
+		updated = append(updated, r.filters[i+1:]...)
+		r.filters = updated
+		return
+	}
+}
+
+// accept 依次执行过滤器，全部通过才返回 true；过滤器 panic 时丢弃该事件
+func (r *EventRouter) accept(event *Event) (ok bool) {
+	r.mu.RLock()
+	filters := r.filters
+	r.mu.RUnlock()
+	if len(filters) == 0 {
+		return true
+	}
+
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Errorf("事件过滤器发生panic: %v", rec)
+			ok = false
+		}
+	}()
+	for _, f := range filters {
+		if !f.fn(event) {
+			return false
+		}
+	}
+	return true
+}
+
+// EnableMessageDedup 开启消息级幂等：近期（ttl 内）处理过的 msg_id 再次到达时直接跳过处理器
+// 用于 WebSocket 补发、Webhook 重试等导致同一事件重复投递的场景；size 为最多记录的条数（LRU 淘汰）。
+// size、ttl 不大于 0 时使用 DefaultMessageDedupSize、DefaultMessageDedupTTL，默认关闭
+func (r *EventRouter) EnableMessageDedup(size int, ttl time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dedup = newMsgDeduplicator(size, ttl)
+}
+
+// DisableMessageDedup 关闭消息级幂等
+func (r *EventRouter) DisableMessageDedup() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.dedup = nil
+}
+
+// duplicate 开启消息级幂等时判断事件是否已处理过
+func (r *EventRouter) duplicate(event *Event) bool {
+	r.mu.RLock()
+	dedup := r.dedup
+	r.mu.RUnlock()
+	if dedup == nil {
+		return false
+	}
+
+	key := eventDedupKey(event)
+	if key == "" || !dedup.Seen(key, time.Now()) {
+		return false
+	}
+	r.logger.Debugf("忽略重复事件: 类型=%d, msg_id=%s", event.Type, event.MsgID)
+	return true
+}
+
+// Dispatch 把事件异步分发给该类型的全部处理器
+func (r *EventRouter) Dispatch(event *Event) {
+	r.dispatch(context.Background(), event, r.spawn)
+}
+
+// DispatchContext 以指定上下文把事件异步分发给该类型的全部处理器
+func (r *EventRouter) DispatchContext(ctx context.Context, event *Event) {
+	r.dispatch(ctx, event, r.spawn)
+}
+
+// handlersFor 返回事件需要调用的处理器：先是 catch-all 处理器，再是该类型的处理器，
+// 该类型没有处理器时换成 OnUnhandled 注册的兜底处理器
+func (r *EventRouter) handlersFor(eventType int) []EventHandlerCtx {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	catchAll := r.handlers[anyEventType]
+	typed := r.handlers[eventType]
+	if len(typed) == 0 {
+		typed = r.handlers[unhandledEventType]
+	}
+	handlers := make([]EventHandlerCtx, 0, len(catchAll)+len(typed))
+	for _, h := range catchAll {
+		handlers = append(handlers, h.fn)
+	}
+	for _, h := range typed {
+		handlers = append(handlers, h.fn)
+	}
+	return handlers
+}
+
+// dispatch 对事件执行过滤与去重后，把需要调用的每个处理器交给 run 执行
+// run 决定处理器的执行方式：invoke 同步依次调用，spawn 各自启动协程，worker pool 则投递到队列。
+// 被过滤器丢弃的事件不计入消息去重
+func (r *EventRouter) dispatch(ctx context.Context, event *Event, run func(context.Context, EventHandlerCtx, *Event)) {
+	if !r.accept(event) || r.duplicate(event) {
+		return
+	}
+
+	for _, h := range r.handlersFor(event.Type) {
+		run(ctx, h, event)
+	}
+}
+
+// spawn 在新协程中调用处理器，waitInflight 可等待其结束
+func (r *EventRouter) spawn(ctx context.Context, h EventHandlerCtx, event *Event) {
+	r.inflight.Add(1)
+	go func() {
+		defer r.inflight.Done()
+		r.invoke(ctx, h, event)
+	}()
+}
+
+// invoke 调用单个事件处理器并恢复其中的panic
+func (r *EventRouter) invoke(ctx context.Context, h EventHandlerCtx, event *Event) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.logger.Errorf("事件处理器发生panic: %v", rec)
+		}
+	}()
+	h(ctx, event)
+}
+
+// eventContextKey 事件处理器上下文中保存当前事件的键
+type eventContextKey struct{}
+
+// withEventContext 派生携带当前事件的处理器上下文
+func withEventContext(ctx context.Context, event *Event) context.Context {
+	return context.WithValue(ctx, eventContextKey{}, event)
+}
+
+// EventFromContext 从处理器上下文中取出当前事件，不在事件处理器中时返回 nil
+func EventFromContext(ctx context.Context) *Event {
+	event, _ := ctx.Value(eventContextKey{}).(*Event)
+	return event
+}
+
+// waitInflight 等待异步分发中的处理器全部结束
+func (r *EventRouter) waitInflight() {
+	r.inflight.Wait()
+}
+
+// parseEvent 解析事件数据，Webhook 与 WebSocket 共用，保证两种来源的 Event 结构一致
+func parseEvent(data json.RawMessage) (*Event, error) {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("解析事件失败: %w", err)
+	}
+	event.cache = &eventCache{}
+	return &event, nil
+}
@@ -0,0 +1,25 @@
+package kook
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventRouterSyncDispatchContinuesAfterError(t *testing.T) {
+	r := NewEventRouter(nil)
+	var calls []string
+	r.OnEventErr(EventTypeTextMessage, func(ctx context.Context, event *Event) error {
+		calls = append(calls, "first")
+		return errors.New("写入失败")
+	})
+	r.OnEventCtx(EventTypeTextMessage, func(ctx context.Context, event *Event) {
+		calls = append(calls, "second")
+	})
+
+	r.dispatch(context.Background(), &Event{Type: EventTypeTextMessage}, r.invoke)
+
+	assert.Equal(t, []string{"first", "second"}, calls)
+}
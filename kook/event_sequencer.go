@@ -0,0 +1,88 @@
+package kook
+
+import (
+	"sync"
+)
+
+// DefaultEventBufferSize 乱序事件缓冲的默认最大条数
+const DefaultEventBufferSize = 1024
+
+// eventSequencer 按 sn 对网关事件去重并排序
+// 断线恢复（resume）后服务端会补发缺失的事件，先到的后续事件暂存在 pending 中，
+// 等缺口补齐后再按 sn 顺序一起分发
+type eventSequencer struct {
+	mu      sync.Mutex
+	pending map[int64]*Event
+	max     int
+}
+
+// newEventSequencer 创建事件排序器，max <= 0 时使用默认值
+func newEventSequencer(max int) *eventSequencer {
+	if max <= 0 {
+		max = DefaultEventBufferSize
+	}
+	return &eventSequencer{
+		pending: make(map[int64]*Event),
+		max:     max,
+	}
+}
+
+// Push 提交一条事件，返回按 sn 顺序可以分发的事件
+// duplicate 表示该 sn 已处理或已在缓冲中；overflow 表示缓冲超限，已跳过缺口强制推进
+func (q *eventSequencer) Push(session *sessionState, sn int64, event *Event) (ready []*Event, duplicate, overflow bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	current := session.LoadSN()
+	if sn <= current {
+		return nil, true, false
+	}
+	if _, ok := q.pending[sn]; ok {
+		return nil, true, false
+	}
+	q.pending[sn] = event
+
+	if sn != current+1 && len(q.pending) > q.max {
+		// 缺口迟迟未补齐，从最小的已缓冲 sn 继续
+		current = q.minPendingLocked() - 1
+		overflow = true
+	}
+
+	for {
+		next, ok := q.pending[current+1]
+		if !ok {
+			break
+		}
+		delete(q.pending, current+1)
+		current++
+		ready = append(ready, next)
+	}
+
+	session.StoreSN(current)
+	return ready, false, overflow
+}
+
+// Pending 返回当前缓冲中的事件条数
+func (q *eventSequencer) Pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Reset 清空缓冲（新会话开始时调用）
+func (q *eventSequencer) Reset() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.pending = make(map[int64]*Event)
+}
+
+// minPendingLocked 返回缓冲中最小的 sn，调用方需持有锁
+func (q *eventSequencer) minPendingLocked() int64 {
+	min := int64(-1)
+	for sn := range q.pending {
+		if min < 0 || sn < min {
+			min = sn
+		}
+	}
+	return min
+}
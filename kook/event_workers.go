@@ -0,0 +1,116 @@
+package kook
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultEventQueueSize 事件 worker pool 的默认队列长度
+const DefaultEventQueueSize = 1024
+
+// eventJob 待执行的事件处理任务
+type eventJob struct {
+	ctx     context.Context
+	handler EventHandlerCtx
+	event   *Event
+}
+
+// eventWorkerPool 固定数量 worker 消费有界队列中的事件处理任务
+type eventWorkerPool struct {
+	jobs       chan eventJob
+	dropOnFull bool
+	dropped    atomic.Uint64
+	invoke     func(context.Context, EventHandlerCtx, *Event)
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
+}
+
+// newEventWorkerPool 创建并启动 worker pool，invoke 负责调用处理器并恢复 panic
+func newEventWorkerPool(workers, queueSize int, dropOnFull bool, invoke func(context.Context, EventHandlerCtx, *Event)) *eventWorkerPool {
+	if queueSize <= 0 {
+		queueSize = DefaultEventQueueSize
+	}
+
+	p := &eventWorkerPool{
+		jobs:       make(chan eventJob, queueSize),
+		dropOnFull: dropOnFull,
+		invoke:     invoke,
+		quit:       make(chan struct{}),
+	}
+
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// work 持续消费任务直到 pool 关闭
+func (p *eventWorkerPool) work() {
+	defer p.wg.Done()
+	for {
+		select {
+		case <-p.quit:
+			return
+		case job := <-p.jobs:
+			p.invoke(job.ctx, job.handler, job.event)
+		}
+	}
+}
+
+// submitResult 投递任务的结果
+type submitResult int
+
+const (
+	submitAccepted  submitResult = iota // 已进入队列
+	submitQueueFull                     // 队列已满，按配置丢弃
+	submitCanceled                      // 等待队列空位时 ctx 结束
+	submitClosed                        // pool 已关闭
+)
+
+// submit 投递任务；队列已满时按配置丢弃或阻塞，直到 ctx 结束。未被接收时返回原因并计入丢弃数
+// pool 关闭后不再接收任务，避免任务进入已无 worker 消费的队列
+func (p *eventWorkerPool) submit(ctx context.Context, job eventJob) submitResult {
+	select {
+	case <-p.quit:
+		p.dropped.Add(1)
+		return submitClosed
+	default:
+	}
+
+	if p.dropOnFull {
+		select {
+		case p.jobs <- job:
+			return submitAccepted
+		default:
+			p.dropped.Add(1)
+			return submitQueueFull
+		}
+	}
+
+	select {
+	case p.jobs <- job:
+		return submitAccepted
+	case <-ctx.Done():
+		p.dropped.Add(1)
+		return submitCanceled
+	case <-p.quit:
+		p.dropped.Add(1)
+		return submitClosed
+	}
+}
+
+// stop 通知全部 worker 退出，队列中尚未执行的任务会被丢弃；正在执行的任务结束后 worker 才退出
+func (p *eventWorkerPool) stop() {
+	p.closeOnce.Do(func() {
+		close(p.quit)
+	})
+}
+
+// wait 等待全部 worker 退出，不能在 worker 中调用
+func (p *eventWorkerPool) wait() {
+	p.wg.Wait()
+}
@@ -0,0 +1,32 @@
+package kook
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWorkerPoolSubmitResults 投递失败时返回具体原因，关闭后的任务不会进入队列
+func TestWorkerPoolSubmitResults(t *testing.T) {
+	noop := func(context.Context, EventHandlerCtx, *Event) {}
+
+	// 没有 worker 消费，队列长度为 1
+	dropping := newEventWorkerPool(0, 1, true, noop)
+	assert.Equal(t, submitAccepted, dropping.submit(context.Background(), eventJob{}))
+	assert.Equal(t, submitQueueFull, dropping.submit(context.Background(), eventJob{}))
+
+	blocking := newEventWorkerPool(0, 1, false, noop)
+	assert.Equal(t, submitAccepted, blocking.submit(context.Background(), eventJob{}))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, submitCanceled, blocking.submit(ctx, eventJob{}))
+
+	closed := newEventWorkerPool(0, 4, false, noop)
+	closed.stop()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, submitClosed, closed.submit(context.Background(), eventJob{}))
+	}
+	assert.Empty(t, closed.jobs, "关闭后的任务不进入队列")
+	assert.Equal(t, uint64(10), closed.dropped.Load())
+}
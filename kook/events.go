@@ -1,5 +1,13 @@
 package kook
 
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
 // 事件类型常量
 const (
 	// 消息事件
@@ -53,32 +61,42 @@ const (
 	MessageTypeSystem = 255 // 系统消息
 )
 
-// 角色权限常量
-const (
-	PermissionViewChannel      = 1 << 0  // 查看频道
-	PermissionSendMessages     = 1 << 1  // 发送消息
-	PermissionManageMessages   = 1 << 2  // 管理消息
-	PermissionManageChannels   = 1 << 3  // 管理频道
-	PermissionConnectVoice     = 1 << 4  // 连接语音频道
-	PermissionSpeakVoice       = 1 << 5  // 语音频道中说话
-	PermissionMuteMembers      = 1 << 6  // 禁言成员
-	PermissionDeafenMembers    = 1 << 7  // 阻止成员听见
-	PermissionMoveMembers      = 1 << 8  // 移动成员
-	PermissionUseVoiceActivity = 1 << 9  // 使用按键说话
-	PermissionManageRoles      = 1 << 10 // 管理角色
-	PermissionManageGuild      = 1 << 11 // 管理服务器
-	PermissionCreateInvite     = 1 << 12 // 创建邀请
-	PermissionManageInvites    = 1 << 13 // 管理邀请
-	PermissionManageEmojis     = 1 << 14 // 管理表情
-	PermissionKickMembers      = 1 << 15 // 踢出成员
-	PermissionBanMembers       = 1 << 16 // 封禁成员
-	PermissionMentionEveryone  = 1 << 17 // 提及所有人
-	PermissionAddReactions     = 1 << 18 // 添加回应
-	PermissionUploadFiles      = 1 << 19 // 上传文件
-	PermissionUseSlashCommands = 1 << 20 // 使用斜杠命令
-	PermissionPlayMusic        = 1 << 21 // 播放音乐
-	PermissionAdministrator    = 1 << 22 // 管理员
-)
+// MessageType 消息类型，取值为 MessageType* 常量，用于日志输出与类型校验
+type MessageType int
+
+// String 返回消息类型的可读名称
+func (t MessageType) String() string {
+	switch t {
+	case MessageTypeText:
+		return "文本消息"
+	case MessageTypeImage:
+		return "图片消息"
+	case MessageTypeVideo:
+		return "视频消息"
+	case MessageTypeFile:
+		return "文件消息"
+	case MessageTypeAudio:
+		return "音频消息"
+	case MessageTypeKMD:
+		return "KMarkdown消息"
+	case MessageTypeCard:
+		return "卡片消息"
+	case MessageTypeSystem:
+		return "系统消息"
+	default:
+		return fmt.Sprintf("未知消息类型(%d)", int(t))
+	}
+}
+
+// Sendable 判断该类型是否可以通过 API 主动发送，系统消息等只会由服务端下发
+func (t MessageType) Sendable() bool {
+	switch t {
+	case MessageTypeText, MessageTypeImage, MessageTypeVideo, MessageTypeFile,
+		MessageTypeAudio, MessageTypeKMD, MessageTypeCard:
+		return true
+	}
+	return false
+}
 
 // GetEventTypeName 获取事件类型名称
 func GetEventTypeName(eventType int) string {
@@ -118,4 +136,168 @@ func GetEventTypeName(eventType int) string {
 	default:
 		return "未知事件"
 	}
-} 
\ No newline at end of file
+}
+
+// EventExtra 事件 extra 的强类型结构
+// 频道/私聊消息的 extra 是扁平对象，系统事件（type 255）的 extra 则为 {type, body}
+type EventExtra struct {
+	Type         string          `json:"-"` // 消息事件为消息类型（如 "9"），系统事件为子类型（如 "added_reaction"）
+	GuildID      string          `json:"guild_id"`
+	ChannelName  string          `json:"channel_name"`
+	Code         string          `json:"code"` // 私聊会话Code
+	Mention      []string        `json:"mention"`
+	MentionRoles []int           `json:"mention_roles"`
+	MentionAll   bool            `json:"mention_all"`
+	MentionHere  bool            `json:"mention_here"`
+	Author       User            `json:"author"`
+	Attachments  []Attachment    `json:"-"`
+	Quote        *Quote          `json:"quote"`
+	Body         json.RawMessage `json:"body"` // 系统事件数据
+}
+
+// UnmarshalJSON 兼容 type 为数字或字符串、attachments 为对象或数组的情况
+func (e *EventExtra) UnmarshalJSON(data []byte) error {
+	type eventExtraAlias EventExtra
+	aux := struct {
+		*eventExtraAlias
+		Type        json.RawMessage `json:"type"`
+		Attachments json.RawMessage `json:"attachments"`
+	}{eventExtraAlias: (*eventExtraAlias)(e)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.Type) > 0 {
+		var typeName string
+		if err := json.Unmarshal(aux.Type, &typeName); err != nil {
+			var typeNum int
+			if err := json.Unmarshal(aux.Type, &typeNum); err != nil {
+				return fmt.Errorf("无法识别的extra.type: %s", string(aux.Type))
+			}
+			typeName = strconv.Itoa(typeNum)
+		}
+		e.Type = typeName
+	}
+
+	if string(bytes.TrimSpace(e.Body)) == "null" {
+		e.Body = nil
+	}
+
+	attachments, err := decodeAttachments(aux.Attachments)
+	if err != nil {
+		return err
+	}
+	e.Attachments = attachments
+
+	return nil
+}
+
+// decodeAttachments 解析附件，兼容单个对象与数组两种结构
+func decodeAttachments(raw json.RawMessage) ([]Attachment, error) {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case len(raw) == 0 || string(raw) == "null":
+		return nil, nil
+	case raw[0] == '[':
+		var attachments []Attachment
+		if err := json.Unmarshal(raw, &attachments); err != nil {
+			return nil, fmt.Errorf("解析附件失败: %w", err)
+		}
+		return attachments, nil
+	default:
+		var single Attachment
+		if err := json.Unmarshal(raw, &single); err != nil {
+			return nil, fmt.Errorf("解析附件失败: %w", err)
+		}
+		return []Attachment{single}, nil
+	}
+}
+
+// IsSystem 判断是否为系统事件的 extra
+func (e *EventExtra) IsSystem() bool {
+	return len(e.Body) > 0
+}
+
+// ParseExtra 解析事件的 extra 字段
+func (e *Event) ParseExtra() (*EventExtra, error) {
+	if e.Extra == nil {
+		return &EventExtra{}, nil
+	}
+
+	data, err := json.Marshal(e.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("序列化事件extra失败: %w", err)
+	}
+
+	var extra EventExtra
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, fmt.Errorf("解析事件extra失败: %w", err)
+	}
+	return &extra, nil
+}
+
+// eventCache 事件 extra 的解析结果，同一事件的多个处理器并发调用访问器时只解析一次
+type eventCache struct {
+	once      sync.Once
+	extra     *EventExtra
+	channelID string // 系统事件 body 中的 channel_id
+}
+
+// parsed 返回惰性解析的 extra；手动构造的 Event 没有缓存，每次调用都重新解析
+func (e *Event) parsed() *eventCache {
+	c := e.cache
+	if c == nil {
+		c = &eventCache{}
+	}
+	c.once.Do(func() {
+		extra, err := e.ParseExtra()
+		if err != nil {
+			extra = &EventExtra{}
+		}
+		c.extra = extra
+		if len(extra.Body) > 0 {
+			var body struct {
+				ChannelID string `json:"channel_id"`
+			}
+			if json.Unmarshal(extra.Body, &body) == nil {
+				c.channelID = body.ChannelID
+			}
+		}
+	})
+	return c
+}
+
+// GuildID 返回事件所属的服务器ID，私聊事件为空
+func (e *Event) GuildID() string {
+	if id := e.parsed().extra.GuildID; id != "" {
+		return id
+	}
+	// 服务器内系统事件的 target_id 即服务器ID
+	if e.IsSystemMessage() && e.ChannelType == "GROUP" {
+		return e.TargetID
+	}
+	return ""
+}
+
+// ChannelID 返回事件所在的频道ID：频道消息为 target_id，系统事件取 body 中的 channel_id，私聊为空
+func (e *Event) ChannelID() string {
+	switch {
+	case e.IsDirectMessage():
+		return ""
+	case e.IsSystemMessage():
+		return e.parsed().channelID
+	default:
+		return e.TargetID
+	}
+}
+
+// IsDirectMessage 判断是否为私聊事件
+func (e *Event) IsDirectMessage() bool {
+	return e.ChannelType == "PERSON"
+}
+
+// IsSystemMessage 判断是否为系统事件（type 255）
+func (e *Event) IsSystemMessage() bool {
+	return e.Type == MessageTypeSystem
+}
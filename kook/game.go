@@ -3,6 +3,7 @@ package kook
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -166,6 +167,41 @@ func (s *GameService) DeleteMusicActivity(ctx context.Context) error {
 	return s.DeleteActivity(ctx, 2)
 }
 
+// IterateGames 创建游戏列表迭代器，gameType 为空时返回全部类型（见 GameTypeAll 等常量）
+func (s *GameService) IterateGames(ctx context.Context, gameType string) *PageIterator[Game] {
+	query := make(map[string]string)
+	if gameType != "" {
+		query["type"] = gameType
+	}
+	return newPageIterator[Game](s.client, "game", query, 50)
+}
+
+// ListGames 获取全部游戏（自动翻页）
+func (s *GameService) ListGames(ctx context.Context) ([]Game, error) {
+	return s.IterateGames(ctx, "").All(ctx)
+}
+
+// SetPlaying 把机器人的动态设置为正在玩指定游戏
+func (s *GameService) SetPlaying(ctx context.Context, gameID int) error {
+	return s.AddGameActivity(ctx, gameID)
+}
+
+// SetListeningMusic 把机器人的动态设置为正在听指定歌曲，Software 为空时默认网易云音乐
+func (s *GameService) SetListeningMusic(ctx context.Context, params MusicActivityParams) error {
+	return s.AddMusicActivity(ctx, params)
+}
+
+// ClearActivity 清除机器人的全部动态（正在玩与正在听）
+// 两种动态分别删除，任一失败时返回合并后的错误
+func (s *GameService) ClearActivity(ctx context.Context) error {
+	gameErr := s.DeleteGameActivity(ctx)
+	musicErr := s.DeleteMusicActivity(ctx)
+	if gameErr != nil || musicErr != nil {
+		return fmt.Errorf("清除动态失败: %w", errors.Join(gameErr, musicErr))
+	}
+	return nil
+}
+
 // 数据结构定义
 
 // Game 游戏信息
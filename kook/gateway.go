@@ -31,6 +31,25 @@ func (s *GatewayService) GetGateway(ctx context.Context, compress int) (*Gateway
 	return &gateway, nil
 }
 
+// GetGateway 获取 WebSocket 网关地址（gateway/index），返回的 wss URL 已携带鉴权参数
+// 可用于自行管理网关连接，compress 为 true 时服务端下发 zlib 压缩的数据
+func (c *Client) GetGateway(ctx context.Context, compress bool) (string, error) {
+	flag := 0
+	if compress {
+		flag = 1
+	}
+
+	gateway, err := c.Gateway.GetGateway(ctx, flag)
+	if err != nil {
+		return "", err
+	}
+	if gateway.URL == "" {
+		return "", fmt.Errorf("网关地址为空")
+	}
+
+	return gateway.URL, nil
+}
+
 // GetVoiceGateway 获取语音网关连接信息
 func (s *GatewayService) GetVoiceGateway(ctx context.Context, channelID string) (*VoiceGateway, error) {
 	if channelID == "" {
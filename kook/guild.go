@@ -40,7 +40,14 @@ func (s *GuildService) GetGuildList(ctx context.Context, page, pageSize int, sor
 }
 
 // GetGuildInfo 获取服务器信息
+//
+// Deprecated: 使用 GetGuild
 func (s *GuildService) GetGuildInfo(ctx context.Context, guildID string) (*Guild, error) {
+	return s.GetGuild(ctx, guildID)
+}
+
+// GetGuild 获取服务器详情，包含频道、角色与服务器表情
+func (s *GuildService) GetGuild(ctx context.Context, guildID string) (*Guild, error) {
 	if guildID == "" {
 		return nil, fmt.Errorf("服务器ID不能为空")
 	}
@@ -231,6 +238,83 @@ func (s *GuildService) GetGuildMembers(ctx context.Context, guildID string, page
 	return &result, nil
 }
 
+// ListGuildMembersParams 服务器成员列表查询参数
+type ListGuildMembersParams struct {
+	ChannelID      string // 语音频道ID，仅返回该频道中的成员
+	Search         string // 按昵称/用户名关键词搜索
+	RoleID         int    // 按角色ID筛选
+	MobileVerified *bool  // 按手机验证状态筛选
+	ActiveTime     *int   // 按活跃时间排序：0 顺序，1 倒序
+	JoinedAt       *int   // 按加入时间排序：0 顺序，1 倒序
+	FilterUserID   string // 获取指定用户
+	Online         *bool  // 按在线状态筛选（接口不支持，由迭代器在本地过滤）
+	PageSize       int    // 每页数量，最大50
+}
+
+// query 构建查询参数（不含分页参数）
+func (p ListGuildMembersParams) query(guildID string) map[string]string {
+	query := map[string]string{
+		"guild_id": guildID,
+	}
+	if p.ChannelID != "" {
+		query["channel_id"] = p.ChannelID
+	}
+	if p.Search != "" {
+		query["search"] = p.Search
+	}
+	if p.RoleID > 0 {
+		query["role_id"] = strconv.Itoa(p.RoleID)
+	}
+	if p.MobileVerified != nil {
+		if *p.MobileVerified {
+			query["mobile_verified"] = "1"
+		} else {
+			query["mobile_verified"] = "0"
+		}
+	}
+	if p.ActiveTime != nil {
+		query["active_time"] = strconv.Itoa(*p.ActiveTime)
+	}
+	if p.JoinedAt != nil {
+		query["joined_at"] = strconv.Itoa(*p.JoinedAt)
+	}
+	if p.FilterUserID != "" {
+		query["filter_user_id"] = p.FilterUserID
+	}
+	return query
+}
+
+// MemberIterator 服务器成员迭代器
+type MemberIterator = PageIterator[GuildMember]
+
+// IterateMembers 创建服务器成员迭代器，PageSize 为空或超过50时按50处理
+func (s *GuildService) IterateMembers(ctx context.Context, guildID string, params ListGuildMembersParams) *MemberIterator {
+	if guildID == "" {
+		return newPageIteratorError[GuildMember](fmt.Errorf("服务器ID不能为空"))
+	}
+	if params.PageSize <= 0 || params.PageSize > 50 {
+		params.PageSize = 50
+	}
+
+	it := newPageIterator[GuildMember](s.client, "guild/user-list", params.query(guildID), params.PageSize)
+	if params.Online != nil {
+		online := *params.Online
+		it.filter = func(m *GuildMember) bool { return m.Online == online }
+	}
+	return it
+}
+
+// IterateGuilds 创建当前用户加入的服务器列表迭代器
+func (s *GuildService) IterateGuilds(ctx context.Context) *PageIterator[Guild] {
+	return newPageIterator[Guild](s.client, "guild/list", nil, 50)
+}
+
+// ListGuilds 获取当前用户（机器人）加入的全部服务器（自动翻页）
+// 列表接口不返回频道与角色，需要详情时对单个服务器调用 GetGuild
+func (s *GuildService) ListGuilds(ctx context.Context) ([]Guild, error) {
+	return s.IterateGuilds(ctx).All(ctx)
+}
+
 // GetGuildMember 获取服务器成员信息
 func (s *GuildService) GetGuildMember(ctx context.Context, guildID, userID string) (*GuildMember, error) {
 	if guildID == "" {
@@ -259,7 +343,14 @@ func (s *GuildService) GetGuildMember(ctx context.Context, guildID, userID strin
 }
 
 // KickGuildMember 踢出服务器成员
+//
+// Deprecated: 使用 KickUser
 func (s *GuildService) KickGuildMember(ctx context.Context, guildID, userID string) error {
+	return s.KickUser(ctx, guildID, userID)
+}
+
+// KickUser 把用户踢出服务器
+func (s *GuildService) KickUser(ctx context.Context, guildID, userID string) error {
 	if guildID == "" {
 		return fmt.Errorf("服务器ID不能为空")
 	}
@@ -276,6 +367,87 @@ func (s *GuildService) KickGuildMember(ctx context.Context, guildID, userID stri
 	return err
 }
 
+// MuteType 服务器禁言类型
+type MuteType int
+
+// 服务器禁言类型常量
+const (
+	MuteTypeMic     MuteType = 1 // 麦克风闭麦
+	MuteTypeHeadset MuteType = 2 // 耳机静音
+)
+
+// GuildMuteList 服务器闭麦/静音用户列表
+type GuildMuteList struct {
+	MicUserIDs     []string // 被闭麦的用户ID
+	HeadsetUserIDs []string // 被静音的用户ID
+}
+
+// GetMuteList 获取服务器中被闭麦和被静音的用户ID列表
+func (s *GuildService) GetMuteList(ctx context.Context, guildID string) (*GuildMuteList, error) {
+	if guildID == "" {
+		return nil, fmt.Errorf("服务器ID不能为空")
+	}
+
+	query := map[string]string{
+		"guild_id":    guildID,
+		"return_type": "detail",
+	}
+
+	resp, err := s.client.Get(ctx, "guild-mute/list", query)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Mic struct {
+			UserIDs []string `json:"user_ids"`
+		} `json:"mic"`
+		Headset struct {
+			UserIDs []string `json:"user_ids"`
+		} `json:"headset"`
+	}
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return nil, fmt.Errorf("解析闭麦列表失败: %w", err)
+	}
+
+	return &GuildMuteList{
+		MicUserIDs:     result.Mic.UserIDs,
+		HeadsetUserIDs: result.Headset.UserIDs,
+	}, nil
+}
+
+// MuteUser 服务器闭麦（MuteTypeMic）或静音（MuteTypeHeadset）用户
+func (s *GuildService) MuteUser(ctx context.Context, guildID, userID string, muteType MuteType) error {
+	return s.guildMute(ctx, "guild-mute/create", guildID, userID, muteType)
+}
+
+// UnmuteUser 解除用户的服务器闭麦或静音
+func (s *GuildService) UnmuteUser(ctx context.Context, guildID, userID string, muteType MuteType) error {
+	return s.guildMute(ctx, "guild-mute/delete", guildID, userID, muteType)
+}
+
+// guildMute 添加或删除服务器闭麦/静音
+func (s *GuildService) guildMute(ctx context.Context, endpoint, guildID, userID string, muteType MuteType) error {
+	if guildID == "" {
+		return fmt.Errorf("服务器ID不能为空")
+	}
+	if userID == "" {
+		return fmt.Errorf("用户ID不能为空")
+	}
+	if muteType != MuteTypeMic && muteType != MuteTypeHeadset {
+		return fmt.Errorf("无效的禁言类型: %d", muteType)
+	}
+
+	params := map[string]interface{}{
+		"guild_id": guildID,
+		"user_id":  userID,
+		"type":     int(muteType),
+	}
+
+	_, err := s.client.Post(ctx, endpoint, params)
+	return err
+}
+
 // UpdateGuildMemberNickname 修改服务器成员昵称
 func (s *GuildService) UpdateGuildMemberNickname(ctx context.Context, guildID, userID, nickname string) error {
 	if guildID == "" {
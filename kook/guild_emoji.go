@@ -0,0 +1,112 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+// GuildEmojiService 服务器表情相关API服务（guild-emoji/*）
+type GuildEmojiService struct {
+	client *Client
+}
+
+// GuildEmoji 服务器表情
+type GuildEmoji struct {
+	ID       string `json:"id"`        // 表情ID，格式为 服务器ID/表情码
+	Name     string `json:"name"`      // 表情名称
+	UserInfo User   `json:"user_info"` // 上传者信息
+}
+
+// ReactionID 返回可直接用于 AddReaction / DeleteReaction 的表情标识
+// 服务器表情的 ID 本身已带服务器前缀，无需再拼接
+func (e *GuildEmoji) ReactionID() string {
+	return e.ID
+}
+
+// KMarkdown 返回在 KMarkdown 消息中引用该表情的语法
+func (e *GuildEmoji) KMarkdown() string {
+	return fmt.Sprintf("(emj)%s(emj)[%s]", e.Name, e.ID)
+}
+
+// IterateEmojis 创建服务器表情列表迭代器
+func (s *GuildEmojiService) IterateEmojis(ctx context.Context, guildID string) *PageIterator[GuildEmoji] {
+	if guildID == "" {
+		return newPageIteratorError[GuildEmoji](fmt.Errorf("服务器ID不能为空"))
+	}
+	return newPageIterator[GuildEmoji](s.client, "guild-emoji/list", map[string]string{"guild_id": guildID}, 50)
+}
+
+// ListEmojis 获取服务器的全部表情（自动翻页）
+func (s *GuildEmojiService) ListEmojis(ctx context.Context, guildID string) ([]GuildEmoji, error) {
+	return s.IterateEmojis(ctx, guildID).All(ctx)
+}
+
+// CreateEmoji 上传图片创建服务器表情，name 为空时由服务端随机生成
+// 图片需小于 256KB，name 长度为 2-32 个字符
+func (s *GuildEmojiService) CreateEmoji(ctx context.Context, guildID, name string, img io.Reader) (*GuildEmoji, error) {
+	if guildID == "" {
+		return nil, fmt.Errorf("服务器ID不能为空")
+	}
+	if img == nil {
+		return nil, fmt.Errorf("表情图片不能为空")
+	}
+	if name != "" {
+		if n := utf8.RuneCountInString(name); n < 2 || n > 32 {
+			return nil, fmt.Errorf("表情名称长度必须在2-32之间")
+		}
+	}
+
+	fields := map[string]string{
+		"guild_id": guildID,
+	}
+	if name != "" {
+		fields["name"] = name
+	}
+
+	resp, err := s.client.postMultipart(ctx, "guild-emoji/create", fields, "emoji", "emoji", img)
+	if err != nil {
+		return nil, err
+	}
+
+	var emoji GuildEmoji
+	if err := json.Unmarshal(resp.Data, &emoji); err != nil {
+		return nil, fmt.Errorf("解析表情信息失败: %w", err)
+	}
+
+	return &emoji, nil
+}
+
+// UpdateEmoji 修改服务器表情名称
+func (s *GuildEmojiService) UpdateEmoji(ctx context.Context, id, name string) error {
+	if id == "" {
+		return fmt.Errorf("表情ID不能为空")
+	}
+	if n := utf8.RuneCountInString(name); n < 2 || n > 32 {
+		return fmt.Errorf("表情名称长度必须在2-32之间")
+	}
+
+	params := map[string]interface{}{
+		"id":   id,
+		"name": name,
+	}
+
+	_, err := s.client.Post(ctx, "guild-emoji/update", params)
+	return err
+}
+
+// DeleteEmoji 删除服务器表情
+func (s *GuildEmojiService) DeleteEmoji(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("表情ID不能为空")
+	}
+
+	params := map[string]interface{}{
+		"id": id,
+	}
+
+	_, err := s.client.Post(ctx, "guild-emoji/delete", params)
+	return err
+}
@@ -0,0 +1,111 @@
+package kook
+
+import (
+	"context"
+	"time"
+)
+
+// 官方建议的心跳参数
+const (
+	DefaultHeartbeatInterval = 30 * time.Second
+	DefaultHeartbeatTimeout  = 6 * time.Second
+	// heartbeatMaxMissed 连续超时次数达到该值时判定连接失效
+	heartbeatMaxMissed = 2
+)
+
+// heartbeatMonitor 网关心跳监控
+// 每隔 interval 发送一次 ping，发送后 timeout 内未收到 pong 记为一次超时，
+// 连续 maxMissed 次超时后调用 onDead 并退出
+type heartbeatMonitor struct {
+	interval  time.Duration
+	timeout   time.Duration
+	maxMissed int
+	ping      func() error
+	onDead    func()
+	logger    Logger
+	pong      chan struct{}
+}
+
+// newHeartbeatMonitor 创建心跳监控
+func newHeartbeatMonitor(interval, timeout time.Duration, ping func() error, onDead func(), logger Logger) *heartbeatMonitor {
+	if interval <= 0 {
+		interval = DefaultHeartbeatInterval
+	}
+	if timeout <= 0 {
+		timeout = DefaultHeartbeatTimeout
+	}
+	return &heartbeatMonitor{
+		interval:  interval,
+		timeout:   timeout,
+		maxMissed: heartbeatMaxMissed,
+		ping:      ping,
+		onDead:    onDead,
+		logger:    logger,
+		pong:      make(chan struct{}, 1),
+	}
+}
+
+// Pong 通知已收到 pong，不会阻塞
+func (h *heartbeatMonitor) Pong() {
+	select {
+	case h.pong <- struct{}{}:
+	default:
+	}
+}
+
+// run 运行心跳循环，直到 ctx 取消或判定连接失效
+func (h *heartbeatMonitor) run(ctx context.Context) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// 丢弃上一轮迟到的 pong
+		select {
+		case <-h.pong:
+		default:
+		}
+
+		if err := h.ping(); err != nil {
+			h.logger.Errorf("发送心跳失败: %v", err)
+		} else if h.waitPong(ctx) {
+			if missed > 0 {
+				h.logger.Infof("心跳恢复正常")
+			}
+			missed = 0
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		missed++
+		h.logger.Warnf("心跳超时 (%d/%d)", missed, h.maxMissed)
+		if missed >= h.maxMissed {
+			h.logger.Errorf("连续心跳超时，判定连接失效")
+			h.onDead()
+			return
+		}
+	}
+}
+
+// waitPong 等待 pong，超时或 ctx 取消时返回 false
+func (h *heartbeatMonitor) waitPong(ctx context.Context) bool {
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-h.pong:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,115 @@
+package kook
+
+import (
+	"container/list"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultIdempotencyTTL 发送去重缓存的默认有效期
+const DefaultIdempotencyTTL = 2 * time.Minute
+
+// DefaultIdempotencyMaxEntries 发送去重缓存默认最多保留的 nonce 条数
+const DefaultIdempotencyMaxEntries = 10000
+
+// newNonce 生成 UUID v4 格式的随机 nonce
+func newNonce() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand 不可用时退化为时间戳，仍能区分不同请求
+		return fmt.Sprintf("%032x", time.Now().UnixNano())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// nonceCache 按 nonce 缓存已发送的消息，避免重试导致重复发送
+// 已完成的结果按完成顺序排在 order 中，所有结果的有效期相同，因此队首总是最早过期的一项，
+// 每次发送时从队首清理过期项并按 maxEntries 淘汰最早的结果，缓存大小不会无限增长
+type nonceCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]*nonceEntry
+	order      *list.List // 已完成的 nonce，按完成时间排列
+}
+
+// nonceEntry 单个 nonce 的发送状态
+type nonceEntry struct {
+	done      chan struct{}
+	msg       *Message
+	err       error
+	expiresAt time.Time
+}
+
+// newNonceCache 创建 nonce 缓存，ttl、maxEntries 不大于 0 时使用默认值
+func newNonceCache(ttl time.Duration, maxEntries int) *nonceCache {
+	if ttl <= 0 {
+		ttl = DefaultIdempotencyTTL
+	}
+	if maxEntries <= 0 {
+		maxEntries = DefaultIdempotencyMaxEntries
+	}
+	return &nonceCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*nonceEntry),
+		order:      list.New(),
+	}
+}
+
+// do 对相同 nonce 只执行一次 send：发送中的请求会等待其结果，成功结果在 TTL 内直接复用
+// 发送失败的 nonce 不会被缓存，允许调用方重试
+func (c *nonceCache) do(nonce string, send func() (*Message, error)) (*Message, error) {
+	c.mu.Lock()
+	c.evictLocked(time.Now())
+	if entry, ok := c.entries[nonce]; ok {
+		c.mu.Unlock()
+		<-entry.done
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		msg := *entry.msg
+		return &msg, nil
+	}
+	entry := &nonceEntry{done: make(chan struct{})}
+	c.entries[nonce] = entry
+	c.mu.Unlock()
+
+	msg, err := send()
+
+	c.mu.Lock()
+	entry.msg, entry.err = msg, err
+	if err != nil {
+		delete(c.entries, nonce)
+	} else {
+		entry.expiresAt = time.Now().Add(c.ttl)
+		c.order.PushBack(nonce)
+		c.evictLocked(time.Now())
+	}
+	c.mu.Unlock()
+	close(entry.done)
+
+	if err != nil {
+		return nil, err
+	}
+	result := *msg
+	return &result, nil
+}
+
+// evictLocked 从最早完成的结果开始清理过期项，并把已完成的结果数量限制在 maxEntries 以内，调用方需持有锁
+// 发送中的 nonce 不在 order 中，不会被淘汰
+func (c *nonceCache) evictLocked(now time.Time) {
+	for front := c.order.Front(); front != nil; front = c.order.Front() {
+		nonce := front.Value.(string)
+		entry := c.entries[nonce]
+		if c.order.Len() <= c.maxEntries && now.Before(entry.expiresAt) {
+			return
+		}
+		c.order.Remove(front)
+		delete(c.entries, nonce)
+	}
+}
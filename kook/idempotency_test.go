@@ -0,0 +1,67 @@
+package kook
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNonceCacheStaysBounded 长时间运行时缓存大小不超过 maxEntries，失败的 nonce 不占用缓存
+func TestNonceCacheStaysBounded(t *testing.T) {
+	const maxEntries = 100
+	c := newNonceCache(time.Hour, maxEntries)
+
+	for i := 0; i < maxEntries*50; i++ {
+		nonce := strconv.Itoa(i)
+		msg, err := c.do(nonce, func() (*Message, error) { return &Message{ID: nonce}, nil })
+		require.NoError(t, err)
+		assert.Equal(t, nonce, msg.ID)
+
+		if i%7 == 0 {
+			_, err := c.do("failed-"+nonce, func() (*Message, error) { return nil, errors.New("send failed") })
+			require.Error(t, err)
+		}
+
+		c.mu.Lock()
+		entries, order := len(c.entries), c.order.Len()
+		c.mu.Unlock()
+		require.LessOrEqual(t, entries, maxEntries)
+		require.LessOrEqual(t, order, maxEntries)
+	}
+
+	c.mu.Lock()
+	assert.Equal(t, maxEntries, len(c.entries))
+	assert.Equal(t, maxEntries, c.order.Len())
+	c.mu.Unlock()
+
+	// 最近的结果仍可复用，最早的结果已被淘汰
+	calls := 0
+	send := func() (*Message, error) { calls++; return &Message{ID: "resent"}, nil }
+	msg, err := c.do(strconv.Itoa(maxEntries*50-1), send)
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(maxEntries*50-1), msg.ID)
+	_, err = c.do("0", send)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+// TestNonceCacheExpires 过期的结果在下次发送时被清理
+func TestNonceCacheExpires(t *testing.T) {
+	c := newNonceCache(50*time.Millisecond, 10)
+	for i := 0; i < 5; i++ {
+		_, err := c.do(strconv.Itoa(i), func() (*Message, error) { return &Message{}, nil })
+		require.NoError(t, err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	_, err := c.do("last", func() (*Message, error) { return &Message{}, nil })
+	require.NoError(t, err)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	assert.Equal(t, 1, len(c.entries))
+	assert.Equal(t, 1, c.order.Len())
+}
@@ -0,0 +1,91 @@
+package kook
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// RequestInterceptor 请求拦截器，在每次API请求发送前调用，可读取并修改请求头
+// 返回错误时中断本次请求
+type RequestInterceptor func(*http.Request) error
+
+// ResponseInterceptor 响应拦截器，在收到API响应后、解析响应体前调用
+// 返回错误时中断本次请求
+type ResponseInterceptor func(*http.Response) error
+
+// WithRequestInterceptor 添加请求拦截器，多个拦截器按注册顺序执行
+func WithRequestInterceptor(interceptor RequestInterceptor) ClientOption {
+	return func(c *Client) {
+		if interceptor != nil {
+			c.requestInterceptors = append(c.requestInterceptors, interceptor)
+		}
+	}
+}
+
+// WithResponseInterceptor 添加响应拦截器，多个拦截器按注册顺序执行
+func WithResponseInterceptor(interceptor ResponseInterceptor) ClientOption {
+	return func(c *Client) {
+		if interceptor != nil {
+			c.responseInterceptors = append(c.responseInterceptors, interceptor)
+		}
+	}
+}
+
+// interceptRequest 依次执行请求拦截器
+func (c *Client) interceptRequest(req *http.Request) error {
+	for _, interceptor := range c.requestInterceptors {
+		if err := interceptor(req); err != nil {
+			return fmt.Errorf("请求拦截器中断请求: %w", err)
+		}
+	}
+	return nil
+}
+
+// interceptResponse 依次执行响应拦截器
+func (c *Client) interceptResponse(resp *http.Response) error {
+	for _, interceptor := range c.responseInterceptors {
+		if err := interceptor(resp); err != nil {
+			return fmt.Errorf("响应拦截器中断请求: %w", err)
+		}
+	}
+	return nil
+}
+
+// SendHook 消息发送钩子，见 WithSendHook
+type SendHook struct {
+	Before func(params *SendMessageParams) error
+	After  func(msg *Message, err error)
+}
+
+// WithSendHook 为 MessageService.SendMessage（以及基于它的卡片、图片等发送方法）添加前置/后置钩子
+// before 在校验参数前调用，可修改参数，返回错误时不发送；after 在发送结束后调用，
+// 被 before 拦截的消息同样会以该错误调用 after。两者均可为 nil，多个钩子按注册顺序执行
+func WithSendHook(before func(*SendMessageParams) error, after func(*Message, error)) ClientOption {
+	return func(c *Client) {
+		if before != nil || after != nil {
+			c.sendHooks = append(c.sendHooks, SendHook{Before: before, After: after})
+		}
+	}
+}
+
+// beforeSend 依次执行前置钩子
+func (c *Client) beforeSend(params *SendMessageParams) error {
+	for _, hook := range c.sendHooks {
+		if hook.Before == nil {
+			continue
+		}
+		if err := hook.Before(params); err != nil {
+			return fmt.Errorf("发送钩子中断发送: %w", err)
+		}
+	}
+	return nil
+}
+
+// afterSend 依次执行后置钩子
+func (c *Client) afterSend(msg *Message, err error) {
+	for _, hook := range c.sendHooks {
+		if hook.After != nil {
+			hook.After(msg, err)
+		}
+	}
+}
@@ -34,48 +34,57 @@ func (s *IntimacyService) GetIntimacy(ctx context.Context, userID string) (*Inti
 	return &intimacy, nil
 }
 
-// UpdateIntimacy 更新用户亲密度
-func (s *IntimacyService) UpdateIntimacy(ctx context.Context, userID string, score int, socialInfo string, imgID string) (*Intimacy, error) {
-	if userID == "" {
-		return nil, fmt.Errorf("用户ID不能为空")
+// UpdateIntimacy 更新用户亲密度，未设置的字段保持不变
+func (s *IntimacyService) UpdateIntimacy(ctx context.Context, params UpdateIntimacyParams) error {
+	if params.UserID == "" {
+		return fmt.Errorf("用户ID不能为空")
 	}
-
-	params := map[string]interface{}{
-		"user_id": userID,
+	if params.Score != nil && (*params.Score < 0 || *params.Score > 2200) {
+		return fmt.Errorf("亲密度分数必须在0-2200之间")
 	}
 
-	if score >= 0 {
-		params["score"] = score
-	}
-	if socialInfo != "" {
-		params["social_info"] = socialInfo
-	}
-	if imgID != "" {
-		params["img_id"] = imgID
+	requestParams := map[string]interface{}{
+		"user_id": params.UserID,
 	}
 
-	resp, err := s.client.Post(ctx, "intimacy/update", params)
-	if err != nil {
-		return nil, err
+	if params.Score != nil {
+		requestParams["score"] = *params.Score
 	}
-
-	var intimacy Intimacy
-	if err := json.Unmarshal(resp.Data, &intimacy); err != nil {
-		return nil, fmt.Errorf("解析亲密度信息失败: %w", err)
+	if params.SocialInfo != "" {
+		requestParams["social_info"] = params.SocialInfo
+	}
+	if params.ImgID != "" {
+		requestParams["img_id"] = params.ImgID
 	}
 
-	return &intimacy, nil
+	_, err := s.client.Post(ctx, "intimacy/update", requestParams)
+	return err
 }
 
 // 数据结构定义
 
 // Intimacy 亲密度信息
 type Intimacy struct {
-	UserID     string `json:"user_id"`     // 用户ID
-	Score      int    `json:"score"`       // 亲密度分数
-	SocialInfo string `json:"social_info"` // 社交信息
-	LastRead   int64  `json:"last_read"`   // 最后阅读时间
-	LastModify int64  `json:"last_modify"` // 最后修改时间
-	ImgID      string `json:"img_id"`      // 图片ID
-	ImgURL     string `json:"img_url"`     // 图片URL
-} 
\ No newline at end of file
+	UserID     string          `json:"user_id"`     // 用户ID
+	Score      int             `json:"score"`       // 亲密度分数
+	SocialInfo string          `json:"social_info"` // 社交信息
+	LastRead   int64           `json:"last_read"`   // 最后阅读时间
+	LastModify int64           `json:"last_modify"` // 最后修改时间
+	ImgID      string          `json:"img_id"`      // 图片ID
+	ImgURL     string          `json:"img_url"`     // 当前形象图片URL
+	ImgList    []IntimacyImage `json:"img_list"`    // 可选形象图片列表
+}
+
+// IntimacyImage 亲密度形象图片
+type IntimacyImage struct {
+	ID  string `json:"id"`  // 图片ID，用于 UpdateIntimacyParams.ImgID
+	URL string `json:"url"` // 图片URL
+}
+
+// UpdateIntimacyParams 更新亲密度参数
+type UpdateIntimacyParams struct {
+	UserID     string // 用户ID
+	Score      *int   // 亲密度分数（0-2200），nil 表示不修改
+	SocialInfo string // 机器人展示的社交信息，空表示不修改
+	ImgID      string // 形象图片ID（取自 Intimacy.ImgList），空表示不修改
+}
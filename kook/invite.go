@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // InviteService 邀请相关API服务
@@ -12,7 +13,7 @@ type InviteService struct {
 	client *Client
 }
 
-// GetInviteList 获取邀请列表
+// GetInviteList 获取单页邀请列表，需要全部数据时使用 ListInvites
 func (s *InviteService) GetInviteList(ctx context.Context, guildID string, page, pageSize int) (*ListInvitesResponse, error) {
 	if guildID == "" {
 		return nil, fmt.Errorf("服务器ID不能为空")
@@ -42,8 +43,40 @@ func (s *InviteService) GetInviteList(ctx context.Context, guildID string, page,
 	return &result, nil
 }
 
-// CreateInvite 创建邀请
+// IterateInvites 创建邀请列表迭代器，guildID 与 channelID 至少提供一个
+func (s *InviteService) IterateInvites(ctx context.Context, guildID, channelID string) *PageIterator[Invite] {
+	if guildID == "" && channelID == "" {
+		return newPageIteratorError[Invite](fmt.Errorf("服务器ID和频道ID不能同时为空"))
+	}
+
+	query := make(map[string]string)
+	if guildID != "" {
+		query["guild_id"] = guildID
+	}
+	if channelID != "" {
+		query["channel_id"] = channelID
+	}
+	return newPageIterator[Invite](s.client, "invite/list", query, 50)
+}
+
+// ListInvites 获取服务器或频道的全部邀请（自动翻页）
+func (s *InviteService) ListInvites(ctx context.Context, guildID, channelID string) ([]Invite, error) {
+	return s.IterateInvites(ctx, guildID, channelID).All(ctx)
+}
+
+// CreateInvite 创建邀请，返回的 Invite 包含完整邀请链接 URL 与邀请码 URLCode
+// Duration、Setting 只接受官方允许的取值，见 InviteDurationXxx 与 InviteUsesXxx 常量
 func (s *InviteService) CreateInvite(ctx context.Context, params CreateInviteParams) (*Invite, error) {
+	if params.GuildID == "" && params.ChannelID == "" {
+		return nil, fmt.Errorf("服务器ID和频道ID不能同时为空")
+	}
+	if !validInviteDuration(params.Duration) {
+		return nil, fmt.Errorf("无效的邀请有效期: %d", params.Duration)
+	}
+	if !validInviteSetting(params.Setting) {
+		return nil, fmt.Errorf("无效的邀请可用次数: %d", params.Setting)
+	}
+
 	requestParams := make(map[string]interface{})
 
 	if params.GuildID != "" {
@@ -52,10 +85,12 @@ func (s *InviteService) CreateInvite(ctx context.Context, params CreateInvitePar
 	if params.ChannelID != "" {
 		requestParams["channel_id"] = params.ChannelID
 	}
-	if params.Duration > 0 {
+	if params.Permanent {
+		requestParams["duration"] = InviteDurationForever
+	} else if params.Duration > 0 {
 		requestParams["duration"] = params.Duration
 	}
-	if params.Setting > 0 {
+	if params.Setting != 0 {
 		requestParams["setting"] = params.Setting
 	}
 
@@ -69,11 +104,23 @@ func (s *InviteService) CreateInvite(ctx context.Context, params CreateInvitePar
 		return nil, fmt.Errorf("解析邀请信息失败: %w", err)
 	}
 
+	// 接口只返回 url，邀请码取自 url 的最后一段
+	if invite.URLCode == "" {
+		invite.URLCode = inviteCode(invite.URL)
+	}
+	if invite.GuildID == "" {
+		invite.GuildID = params.GuildID
+	}
+	if invite.ChannelID == "" {
+		invite.ChannelID = params.ChannelID
+	}
+
 	return &invite, nil
 }
 
-// DeleteInvite 删除邀请
+// DeleteInvite 删除邀请，urlCode 可以是邀请码，也可以是完整的邀请链接
 func (s *InviteService) DeleteInvite(ctx context.Context, urlCode string) error {
+	urlCode = inviteCode(urlCode)
 	if urlCode == "" {
 		return fmt.Errorf("邀请码不能为空")
 	}
@@ -86,29 +133,63 @@ func (s *InviteService) DeleteInvite(ctx context.Context, urlCode string) error
 	return err
 }
 
+// inviteCode 从邀请链接中取出邀请码，传入的已是邀请码时原样返回
+func inviteCode(s string) string {
+	s = strings.TrimRight(strings.TrimSpace(s), "/")
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		s = s[i+1:]
+	}
+	if i := strings.IndexAny(s, "?#"); i >= 0 {
+		s = s[:i]
+	}
+	return s
+}
+
+// validInviteDuration 判断是否为官方允许的邀请有效期，0 表示使用默认值
+func validInviteDuration(d int) bool {
+	switch d {
+	case 0, InviteDurationHalfHour, InviteDurationOneHour, InviteDurationSixHours,
+		InviteDurationTwelveHours, InviteDurationOneDay, InviteDurationOneWeek:
+		return true
+	}
+	return false
+}
+
+// validInviteSetting 判断是否为官方允许的邀请可用次数，0 表示使用默认值
+func validInviteSetting(n int) bool {
+	switch n {
+	case 0, InviteUsesUnlimited, InviteUsesOne, InviteUsesFive, InviteUsesTen,
+		InviteUsesTwentyFive, InviteUsesFifty, InviteUsesHundred:
+		return true
+	}
+	return false
+}
+
 // 数据结构定义
 
 // Invite 邀请信息
 type Invite struct {
-	GuildID     string `json:"guild_id"`     // 服务器ID
-	ChannelID   string `json:"channel_id"`   // 频道ID
-	URLCode     string `json:"url_code"`     // 邀请码
-	URL         string `json:"url"`          // 邀请链接
-	User        User   `json:"user"`         // 创建者信息
-	CreatedAt   int64  `json:"created_at"`   // 创建时间
-	UpdatedAt   int64  `json:"updated_at"`   // 更新时间
-	ExpiredAt   int64  `json:"expired_at"`   // 过期时间
-	Duration    int    `json:"duration"`     // 有效期（秒）
-	Setting     int    `json:"setting"`      // 设置
-	RemainTimes int    `json:"remain_times"` // 剩余使用次数
+	GuildID        string `json:"guild_id"`        // 服务器ID
+	ChannelID      string `json:"channel_id"`      // 频道ID
+	URLCode        string `json:"url_code"`        // 邀请码
+	URL            string `json:"url"`             // 邀请链接
+	User           User   `json:"user"`            // 创建者信息
+	CreatedAt      int64  `json:"created_at"`      // 创建时间
+	UpdatedAt      int64  `json:"updated_at"`      // 更新时间
+	Duration       int    `json:"duration"`        // 有效期（秒）
+	Setting        int    `json:"setting"`         // 设置
+	ExpireTime     int64  `json:"expire_time"`     // 过期时间（秒级时间戳，0 表示永久）
+	RemainingTimes int    `json:"remaining_times"` // 剩余使用次数（-1 表示不限）
+	UsingTimes     int    `json:"using_times"`     // 已使用次数
 }
 
 // CreateInviteParams 创建邀请参数
 type CreateInviteParams struct {
 	GuildID   string `json:"guild_id,omitempty"`   // 服务器ID
 	ChannelID string `json:"channel_id,omitempty"` // 频道ID
-	Duration  int    `json:"duration,omitempty"`   // 有效期（秒）：0永久，1800半小时，3600一小时，21600六小时，43200十二小时，86400一天，604800七天
-	Setting   int    `json:"setting,omitempty"`    // 设置：次数限制，默认-1无限制
+	Duration  int    `json:"duration,omitempty"`   // 有效期（秒）：1800半小时，3600一小时，21600六小时，43200十二小时，86400一天，604800七天；0 使用默认（七天）
+	Permanent bool   `json:"-"`                    // 永久有效，设置后忽略 Duration
+	Setting   int    `json:"setting,omitempty"`    // 可用次数：-1不限，1、5、10、25、50、100；0 使用默认（不限）
 }
 
 // ListInvitesResponse 邀请列表响应
@@ -120,11 +201,22 @@ type ListInvitesResponse struct {
 
 // 邀请有效期常量
 const (
-	InviteDurationForever   = 0      // 永久
-	InviteDurationHalfHour  = 1800   // 半小时
-	InviteDurationOneHour   = 3600   // 一小时
-	InviteDurationSixHours  = 21600  // 六小时
-	InviteDurationTwelveHours = 43200 // 十二小时
-	InviteDurationOneDay    = 86400  // 一天
-	InviteDurationOneWeek   = 604800 // 七天
-) 
\ No newline at end of file
+	InviteDurationForever     = 0      // 永久（通过 CreateInviteParams.Permanent 设置）
+	InviteDurationHalfHour    = 1800   // 半小时
+	InviteDurationOneHour     = 3600   // 一小时
+	InviteDurationSixHours    = 21600  // 六小时
+	InviteDurationTwelveHours = 43200  // 十二小时
+	InviteDurationOneDay      = 86400  // 一天
+	InviteDurationOneWeek     = 604800 // 七天
+)
+
+// 邀请可用次数常量
+const (
+	InviteUsesUnlimited  = -1  // 不限次数
+	InviteUsesOne        = 1   // 1 次
+	InviteUsesFive       = 5   // 5 次
+	InviteUsesTen        = 10  // 10 次
+	InviteUsesTwentyFive = 25  // 25 次
+	InviteUsesFifty      = 50  // 50 次
+	InviteUsesHundred    = 100 // 100 次
+)
@@ -0,0 +1,143 @@
+package kook
+
+import (
+	"strings"
+)
+
+// kmarkdownEscaper 转义 KMarkdown 中具有语法含义的字符
+var kmarkdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`*`, `\*`,
+	`~`, `\~`,
+	"`", "\\`",
+	`(`, `\(`,
+	`)`, `\)`,
+	`[`, `\[`,
+	`]`, `\]`,
+)
+
+// EscapeKMarkdown 转义普通文本，避免被解析为 KMarkdown 标记
+func EscapeKMarkdown(s string) string {
+	return kmarkdownEscaper.Replace(s)
+}
+
+// KMarkdownBuilder KMarkdown 内容构建器
+// 所有接收用户文本的方法都会自动转义，链式调用后通过 Build 获取最终内容
+type KMarkdownBuilder struct {
+	sb strings.Builder
+}
+
+// NewKMarkdownBuilder 创建 KMarkdown 构建器
+func NewKMarkdownBuilder() *KMarkdownBuilder {
+	return &KMarkdownBuilder{}
+}
+
+// Text 追加普通文本（自动转义）
+func (b *KMarkdownBuilder) Text(s string) *KMarkdownBuilder {
+	b.sb.WriteString(EscapeKMarkdown(s))
+	return b
+}
+
+// Raw 追加原始 KMarkdown 内容（不转义）
+func (b *KMarkdownBuilder) Raw(s string) *KMarkdownBuilder {
+	b.sb.WriteString(s)
+	return b
+}
+
+// Newline 追加换行
+func (b *KMarkdownBuilder) Newline() *KMarkdownBuilder {
+	b.sb.WriteString("\n")
+	return b
+}
+
+// Bold 追加加粗文本
+func (b *KMarkdownBuilder) Bold(s string) *KMarkdownBuilder {
+	b.sb.WriteString("**" + EscapeKMarkdown(s) + "**")
+	return b
+}
+
+// Italic 追加斜体文本
+func (b *KMarkdownBuilder) Italic(s string) *KMarkdownBuilder {
+	b.sb.WriteString("*" + EscapeKMarkdown(s) + "*")
+	return b
+}
+
+// Strikethrough 追加删除线文本
+func (b *KMarkdownBuilder) Strikethrough(s string) *KMarkdownBuilder {
+	b.sb.WriteString("~~" + EscapeKMarkdown(s) + "~~")
+	return b
+}
+
+// InlineCode 追加行内代码
+// 代码内的反引号无法转义，会被替换为单引号
+func (b *KMarkdownBuilder) InlineCode(s string) *KMarkdownBuilder {
+	b.sb.WriteString("`" + strings.ReplaceAll(s, "`", "'") + "`")
+	return b
+}
+
+// CodeBlock 追加代码块，lang 可为空
+func (b *KMarkdownBuilder) CodeBlock(lang, s string) *KMarkdownBuilder {
+	b.sb.WriteString("```" + lang + "\n")
+	b.sb.WriteString(strings.ReplaceAll(s, "```", "'''"))
+	if !strings.HasSuffix(s, "\n") {
+		b.sb.WriteString("\n")
+	}
+	b.sb.WriteString("```\n")
+	return b
+}
+
+// MentionUser 追加 @用户
+func (b *KMarkdownBuilder) MentionUser(userID string) *KMarkdownBuilder {
+	b.sb.WriteString("(met)" + userID + "(met)")
+	return b
+}
+
+// MentionRole 追加 @角色
+func (b *KMarkdownBuilder) MentionRole(roleID string) *KMarkdownBuilder {
+	b.sb.WriteString("(rol)" + roleID + "(rol)")
+	return b
+}
+
+// MentionAll 追加 @全体成员
+func (b *KMarkdownBuilder) MentionAll() *KMarkdownBuilder {
+	b.sb.WriteString("(met)all(met)")
+	return b
+}
+
+// MentionHere 追加 @在线成员
+func (b *KMarkdownBuilder) MentionHere() *KMarkdownBuilder {
+	b.sb.WriteString("(met)here(met)")
+	return b
+}
+
+// Channel 追加 #频道
+func (b *KMarkdownBuilder) Channel(channelID string) *KMarkdownBuilder {
+	b.sb.WriteString("(chn)" + channelID + "(chn)")
+	return b
+}
+
+// Emoji 追加服务器表情
+func (b *KMarkdownBuilder) Emoji(name, id string) *KMarkdownBuilder {
+	b.sb.WriteString("(emj)" + EscapeKMarkdown(name) + "(emj)[" + id + "]")
+	return b
+}
+
+// Link 追加超链接
+func (b *KMarkdownBuilder) Link(text, url string) *KMarkdownBuilder {
+	b.sb.WriteString("[" + EscapeKMarkdown(text) + "](" + url + ")")
+	return b
+}
+
+// Build 返回构建好的 KMarkdown 内容
+func (b *KMarkdownBuilder) Build() string {
+	return b.sb.String()
+}
+
+// MessageParams 生成以 KMarkdown 类型发送到目标频道的消息参数
+func (b *KMarkdownBuilder) MessageParams(targetID string) SendMessageParams {
+	return SendMessageParams{
+		TargetID: targetID,
+		Content:  b.Build(),
+		MsgType:  MessageTypeKMD,
+	}
+}
@@ -0,0 +1,306 @@
+package kook
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// KMarkdownTokenType KMarkdown token 类型
+type KMarkdownTokenType int
+
+const (
+	TokenText        KMarkdownTokenType = iota // 普通文本（已去除转义与格式标记）
+	TokenMentionUser                           // @用户，Value 为用户ID
+	TokenMentionAll                            // @全体成员
+	TokenMentionHere                           // @在线成员
+	TokenMentionRole                           // @角色，Value 为角色ID
+	TokenChannel                               // #频道，Value 为频道ID
+	TokenEmoji                                 // 服务器表情，Text 为表情名称，Value 为表情ID
+	TokenLink                                  // 超链接，Text 为链接文字，Value 为URL
+	TokenCode                                  // 行内代码或代码块，Text 为代码内容，代码块的 Value 为语言
+)
+
+// String 返回 token 类型名称
+func (t KMarkdownTokenType) String() string {
+	switch t {
+	case TokenText:
+		return "text"
+	case TokenMentionUser:
+		return "mention_user"
+	case TokenMentionAll:
+		return "mention_all"
+	case TokenMentionHere:
+		return "mention_here"
+	case TokenMentionRole:
+		return "mention_role"
+	case TokenChannel:
+		return "channel"
+	case TokenEmoji:
+		return "emoji"
+	case TokenLink:
+		return "link"
+	case TokenCode:
+		return "code"
+	default:
+		return "unknown"
+	}
+}
+
+// Token KMarkdown 解析结果中的一个片段
+type Token struct {
+	Type  KMarkdownTokenType
+	Text  string
+	Value string
+}
+
+// ParseKMarkdown 把 KMarkdown 内容解析为 token 流
+// 加粗、斜体、删除线等格式标记会被去除，其中嵌套的 mention、表情等照常解析；
+// 转义字符还原为字面字符；未闭合的 mention、链接按普通文本处理，未闭合的代码块返回错误
+func ParseKMarkdown(content string) ([]Token, error) {
+	p := &kmarkdownParser{src: content}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.tokens, nil
+}
+
+// kmarkdownParser KMarkdown 解析器状态
+type kmarkdownParser struct {
+	src    string
+	pos    int
+	text   strings.Builder
+	tokens []Token
+	open   map[string]bool // 尚未闭合的 **、*、~~ 标记
+}
+
+func (p *kmarkdownParser) parse() error {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+
+		switch {
+		case rest[0] == '\\' && len(rest) > 1:
+			_, size := utf8.DecodeRuneInString(rest[1:])
+			p.text.WriteString(rest[1 : 1+size])
+			p.pos += 1 + size
+		case strings.HasPrefix(rest, "```"):
+			if err := p.parseCodeBlock(); err != nil {
+				return err
+			}
+		case rest[0] == '`':
+			p.parseInlineCode()
+		case rest[0] == '(':
+			p.parseTag()
+		case rest[0] == '[':
+			p.parseLink()
+		case rest[0] == '*' || strings.HasPrefix(rest, "~~"):
+			p.skipMarker()
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			p.text.WriteString(rest[:size])
+			p.pos += size
+		}
+	}
+	p.flushText()
+	return nil
+}
+
+// flushText 把累积的文本输出为 TokenText
+func (p *kmarkdownParser) flushText() {
+	if p.text.Len() == 0 {
+		return
+	}
+	p.tokens = append(p.tokens, Token{Type: TokenText, Text: p.text.String()})
+	p.text.Reset()
+}
+
+// emit 输出一个非文本 token
+func (p *kmarkdownParser) emit(tok Token) {
+	p.flushText()
+	p.tokens = append(p.tokens, tok)
+}
+
+// parseCodeBlock 解析 ```lang\ncode``` 代码块
+func (p *kmarkdownParser) parseCodeBlock() error {
+	start := p.pos + 3
+	end := strings.Index(p.src[start:], "```")
+	if end < 0 {
+		return fmt.Errorf("代码块未闭合: 位置 %d", p.pos)
+	}
+
+	body := p.src[start : start+end]
+	lang := ""
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		lang = strings.TrimSpace(body[:nl])
+		body = body[nl+1:]
+	}
+	p.emit(Token{Type: TokenCode, Text: strings.TrimSuffix(body, "\n"), Value: lang})
+	p.pos = start + end + 3
+	return nil
+}
+
+// parseInlineCode 解析 `code`，未闭合时按字面反引号处理
+func (p *kmarkdownParser) parseInlineCode() {
+	end := strings.IndexByte(p.src[p.pos+1:], '`')
+	if end < 0 {
+		p.text.WriteByte('`')
+		p.pos++
+		return
+	}
+	p.emit(Token{Type: TokenCode, Text: p.src[p.pos+1 : p.pos+1+end]})
+	p.pos += end + 2
+}
+
+// parseTag 解析 (met)、(rol)、(chn)、(emj) 以及可忽略的格式标签
+func (p *kmarkdownParser) parseTag() {
+	rest := p.src[p.pos:]
+
+	for _, tag := range []string{"(met)", "(rol)", "(chn)", "(emj)"} {
+		if !strings.HasPrefix(rest, tag) {
+			continue
+		}
+		inner, ok := closedTag(rest[len(tag):], tag)
+		if !ok {
+			break
+		}
+		consumed := len(tag)*2 + len(inner)
+
+		switch tag {
+		case "(met)":
+			switch inner {
+			case "all":
+				p.emit(Token{Type: TokenMentionAll, Text: "@全体成员"})
+			case "here":
+				p.emit(Token{Type: TokenMentionHere, Text: "@在线成员"})
+			default:
+				p.emit(Token{Type: TokenMentionUser, Value: inner})
+			}
+		case "(rol)":
+			p.emit(Token{Type: TokenMentionRole, Value: inner})
+		case "(chn)":
+			p.emit(Token{Type: TokenChannel, Value: inner})
+		case "(emj)":
+			id := ""
+			if after := rest[consumed:]; strings.HasPrefix(after, "[") {
+				if end := strings.IndexByte(after, ']'); end > 0 {
+					id = after[1:end]
+					consumed += end + 1
+				}
+			}
+			p.emit(Token{Type: TokenEmoji, Text: unescapeKMarkdown(inner), Value: id})
+		}
+		p.pos += consumed
+		return
+	}
+
+	if strings.HasPrefix(rest, "(font)") {
+		p.pos += len("(font)")
+		// 闭合的 (font) 后紧跟 [颜色]
+		if after := p.src[p.pos:]; strings.HasPrefix(after, "[") {
+			if end := strings.IndexByte(after, ']'); end > 0 && !strings.HasPrefix(after[end+1:], "(") {
+				p.pos += end + 1
+			}
+		}
+		return
+	}
+	if strings.HasPrefix(rest, "(ins)") || strings.HasPrefix(rest, "(spl)") {
+		p.pos += len("(ins)")
+		return
+	}
+
+	p.text.WriteByte('(')
+	p.pos++
+}
+
+// closedTag 查找闭合标签，返回标签内容；内容为空或跨行时视为未闭合
+func closedTag(s, tag string) (string, bool) {
+	end := strings.Index(s, tag)
+	if end <= 0 {
+		return "", false
+	}
+	inner := s[:end]
+	if strings.ContainsAny(inner, "\n") {
+		return "", false
+	}
+	return inner, true
+}
+
+// parseLink 解析 [文字](url)，不是链接时按字面 [ 处理
+func (p *kmarkdownParser) parseLink() {
+	rest := p.src[p.pos:]
+
+	textEnd := -1
+	for i := 1; i < len(rest); i++ {
+		if rest[i] == '\\' {
+			i++
+			continue
+		}
+		if rest[i] == ']' {
+			textEnd = i
+			break
+		}
+		if rest[i] == '\n' {
+			break
+		}
+	}
+	if textEnd < 0 || !strings.HasPrefix(rest[textEnd+1:], "(") {
+		p.text.WriteByte('[')
+		p.pos++
+		return
+	}
+
+	urlPart := rest[textEnd+2:]
+	urlEnd := strings.IndexAny(urlPart, ")\n")
+	if urlEnd < 0 || urlPart[urlEnd] != ')' {
+		p.text.WriteByte('[')
+		p.pos++
+		return
+	}
+
+	p.emit(Token{
+		Type:  TokenLink,
+		Text:  unescapeKMarkdown(rest[1:textEnd]),
+		Value: urlPart[:urlEnd],
+	})
+	p.pos += textEnd + 2 + urlEnd + 1
+}
+
+// skipMarker 跳过成对出现的 **、*、~~ 格式标记，没有配对的标记按字面字符处理
+func (p *kmarkdownParser) skipMarker() {
+	rest := p.src[p.pos:]
+	for _, marker := range []string{"**", "~~", "*"} {
+		if !strings.HasPrefix(rest, marker) {
+			continue
+		}
+		if p.open == nil {
+			p.open = make(map[string]bool)
+		}
+		switch {
+		case p.open[marker]:
+			p.open[marker] = false
+		case strings.Contains(rest[len(marker):], marker):
+			p.open[marker] = true
+		default:
+			p.text.WriteString(marker)
+		}
+		p.pos += len(marker)
+		return
+	}
+	p.text.WriteByte(rest[0])
+	p.pos++
+}
+
+// unescapeKMarkdown 还原转义字符
+func unescapeKMarkdown(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
@@ -0,0 +1,134 @@
+package kook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Token
+	}{
+		{
+			name:    "普通文本",
+			content: "hello",
+			want:    []Token{{Type: TokenText, Text: "hello"}},
+		},
+		{
+			name:    "转义格式标记",
+			content: `\*not bold\*`,
+			want:    []Token{{Type: TokenText, Text: "*not bold*"}},
+		},
+		{
+			name:    "转义反斜杠",
+			content: `a\\b`,
+			want:    []Token{{Type: TokenText, Text: `a\b`}},
+		},
+		{
+			name:    "转义的标签不再解析为 mention",
+			content: `\(met)123(met)`,
+			want:    []Token{{Type: TokenText, Text: "(met)123(met)"}},
+		},
+		{
+			name:    "转义多字节字符",
+			content: `\中文`,
+			want:    []Token{{Type: TokenText, Text: "中文"}},
+		},
+		{
+			name:    "加粗中嵌套 mention",
+			content: "**hi (met)123(met)**",
+			want: []Token{
+				{Type: TokenText, Text: "hi "},
+				{Type: TokenMentionUser, Value: "123"},
+			},
+		},
+		{
+			name:    "加粗中嵌套斜体",
+			content: "**bold *it* end**",
+			want:    []Token{{Type: TokenText, Text: "bold it end"}},
+		},
+		{
+			name:    "删除线中嵌套链接",
+			content: "~~[a](https://kookapp.cn)~~",
+			want:    []Token{{Type: TokenLink, Text: "a", Value: "https://kookapp.cn"}},
+		},
+		{
+			name:    "链接文字中的转义括号",
+			content: `[a\]b](https://kookapp.cn)`,
+			want:    []Token{{Type: TokenLink, Text: "a]b", Value: "https://kookapp.cn"}},
+		},
+		{
+			name:    "剧透中嵌套频道",
+			content: "(spl)去 (chn)456(chn)(spl)",
+			want: []Token{
+				{Type: TokenText, Text: "去 "},
+				{Type: TokenChannel, Value: "456"},
+			},
+		},
+		{
+			name:    "颜色标签",
+			content: "(font)red(font)[danger]",
+			want:    []Token{{Type: TokenText, Text: "red"}},
+		},
+		{
+			name:    "表情名称中的转义",
+			content: `(emj)a\_b(emj)[1/abc]`,
+			want:    []Token{{Type: TokenEmoji, Text: "a_b", Value: "1/abc"}},
+		},
+		{
+			name:    "全体与在线成员",
+			content: "(met)all(met)(met)here(met)",
+			want: []Token{
+				{Type: TokenMentionAll, Text: "@全体成员"},
+				{Type: TokenMentionHere, Text: "@在线成员"},
+			},
+		},
+		{
+			name:    "角色",
+			content: "(rol)789(rol)",
+			want:    []Token{{Type: TokenMentionRole, Value: "789"}},
+		},
+		{
+			name:    "行内代码中的标记保持原样",
+			content: "`**x** (met)1(met)`",
+			want:    []Token{{Type: TokenCode, Text: "**x** (met)1(met)"}},
+		},
+		{
+			name:    "代码块",
+			content: "```go\nfmt.Println()\n```",
+			want:    []Token{{Type: TokenCode, Text: "fmt.Println()", Value: "go"}},
+		},
+		{
+			name:    "未闭合的加粗按字面处理",
+			content: "**a",
+			want:    []Token{{Type: TokenText, Text: "**a"}},
+		},
+		{
+			name:    "未闭合的 mention 按字面处理",
+			content: "(met)123",
+			want:    []Token{{Type: TokenText, Text: "(met)123"}},
+		},
+		{
+			name:    "不是链接的方括号",
+			content: "[a] b",
+			want:    []Token{{Type: TokenText, Text: "[a] b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKMarkdown(tt.content)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseKMarkdownUnclosedCodeBlock(t *testing.T) {
+	_, err := ParseKMarkdown("```go\nfmt.Println()")
+	assert.Error(t, err)
+}
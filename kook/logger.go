@@ -0,0 +1,88 @@
+package kook
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Logger 日志接口，实现该接口即可接入自定义日志系统
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+	// WithError 返回附带错误信息的日志器
+	WithError(err error) Logger
+}
+
+// slogLogger 基于标准库 slog 的 Logger 实现
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger 用 slog.Logger 创建 Logger，l 为 nil 时使用 slog.Default()
+func NewSlogLogger(l *slog.Logger) Logger {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &slogLogger{l: l}
+}
+
+// newDefaultLogger 创建默认日志器：输出到标准错误，Info 级别
+func newDefaultLogger() Logger {
+	return NewSlogLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo})))
+}
+
+// Debugf 输出 Debug 级别日志
+func (s *slogLogger) Debugf(format string, args ...interface{}) {
+	s.log(slog.LevelDebug, format, args)
+}
+
+// Infof 输出 Info 级别日志
+func (s *slogLogger) Infof(format string, args ...interface{}) {
+	s.log(slog.LevelInfo, format, args)
+}
+
+// Warnf 输出 Warn 级别日志
+func (s *slogLogger) Warnf(format string, args ...interface{}) {
+	s.log(slog.LevelWarn, format, args)
+}
+
+// Errorf 输出 Error 级别日志
+func (s *slogLogger) Errorf(format string, args ...interface{}) {
+	s.log(slog.LevelError, format, args)
+}
+
+// WithError 返回附带 error 属性的日志器
+func (s *slogLogger) WithError(err error) Logger {
+	return &slogLogger{l: s.l.With("error", err)}
+}
+
+// log 在级别开启时才格式化消息
+func (s *slogLogger) log(level slog.Level, format string, args []interface{}) {
+	ctx := context.Background()
+	if !s.l.Enabled(ctx, level) {
+		return
+	}
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	s.l.Log(ctx, level, msg)
+}
+
+// nopLogger 丢弃全部日志
+type nopLogger struct{}
+
+// NopLogger 返回丢弃全部日志的 Logger
+func NopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debugf(string, ...interface{}) {}
+func (nopLogger) Infof(string, ...interface{})  {}
+func (nopLogger) Warnf(string, ...interface{})  {}
+func (nopLogger) Errorf(string, ...interface{}) {}
+func (n nopLogger) WithError(error) Logger      { return n }
@@ -0,0 +1,44 @@
+package kook
+
+import (
+	"github.com/sirupsen/logrus"
+)
+
+// logrusLogger 基于 logrus 的 Logger 实现
+type logrusLogger struct {
+	l logrus.FieldLogger
+}
+
+// NewLogrusLogger 用 logrus 日志器（*logrus.Logger 或 *logrus.Entry）创建 Logger，l 为 nil 时使用 logrus.StandardLogger()
+// 用于从旧版 WithLogger(*logrus.Logger) 迁移：WithLogger(kook.NewLogrusLogger(logger))
+func NewLogrusLogger(l logrus.FieldLogger) Logger {
+	if l == nil {
+		l = logrus.StandardLogger()
+	}
+	return &logrusLogger{l: l}
+}
+
+// Debugf 输出 Debug 级别日志
+func (g *logrusLogger) Debugf(format string, args ...interface{}) {
+	g.l.Debugf(format, args...)
+}
+
+// Infof 输出 Info 级别日志
+func (g *logrusLogger) Infof(format string, args ...interface{}) {
+	g.l.Infof(format, args...)
+}
+
+// Warnf 输出 Warn 级别日志
+func (g *logrusLogger) Warnf(format string, args ...interface{}) {
+	g.l.Warnf(format, args...)
+}
+
+// Errorf 输出 Error 级别日志
+func (g *logrusLogger) Errorf(format string, args ...interface{}) {
+	g.l.Errorf(format, args...)
+}
+
+// WithError 返回附带 error 字段的日志器
+func (g *logrusLogger) WithError(err error) Logger {
+	return &logrusLogger{l: g.l.WithError(err)}
+}
@@ -0,0 +1,27 @@
+package kook
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogrusLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := logrus.New()
+	l.SetOutput(&buf)
+	l.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true, DisableColors: true})
+
+	logger := NewLogrusLogger(l)
+	logger.Debugf("不输出 %d", 1)
+	logger.WithError(errors.New("boom")).Errorf("请求失败: %s", "guild/list")
+
+	out := buf.String()
+	assert.NotContains(t, out, "不输出")
+	assert.Contains(t, out, "level=error")
+	assert.Contains(t, out, `msg="请求失败: guild/list"`)
+	assert.Contains(t, out, "error=boom")
+}
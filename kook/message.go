@@ -1,11 +1,17 @@
 package kook
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
 )
 
 // MessageService 消息相关API服务
@@ -14,7 +20,39 @@ type MessageService struct {
 }
 
 // SendMessage 发送消息
+// Nonce 为空时自动生成 UUID，返回的 Message.Nonce 为最终使用的 nonce
+// 返回的 Message 回填了 Type、TargetID 以及私聊实际使用的 ChatCode，可直接用于后续编辑/删除
+// 配置了 WithSendHook 时，发送前后依次执行钩子
 func (s *MessageService) SendMessage(ctx context.Context, params SendMessageParams) (*Message, error) {
+	if len(s.client.sendHooks) == 0 {
+		return s.send(ctx, params)
+	}
+
+	if err := s.client.beforeSend(&params); err != nil {
+		s.client.afterSend(nil, err)
+		return nil, err
+	}
+	msg, err := s.send(ctx, params)
+	s.client.afterSend(msg, err)
+	return msg, err
+}
+
+// send 补全 nonce，开启本地去重时相同 nonce 直接返回首次发送的结果
+func (s *MessageService) send(ctx context.Context, params SendMessageParams) (*Message, error) {
+	if params.Nonce == "" {
+		params.Nonce = newNonce()
+	}
+
+	if s.client.nonceCache != nil {
+		return s.client.nonceCache.do(params.Nonce, func() (*Message, error) {
+			return s.sendMessage(ctx, params)
+		})
+	}
+	return s.sendMessage(ctx, params)
+}
+
+// sendMessage 执行实际的发送请求
+func (s *MessageService) sendMessage(ctx context.Context, params SendMessageParams) (*Message, error) {
 	var endpoint string
 	requestParams := make(map[string]interface{})
 
@@ -29,11 +67,18 @@ func (s *MessageService) SendMessage(ctx context.Context, params SendMessagePara
 		if params.TargetID == "" && params.ChatCode == "" {
 			return nil, fmt.Errorf("私聊消息必须提供目标ID或会话Code")
 		}
-		if params.TargetID != "" {
-			requestParams["target_id"] = params.TargetID
+		// 只给了目标用户时自动创建（或复用）私聊会话，创建失败则直接按 target_id 发送
+		if params.ChatCode == "" {
+			if code, err := s.client.DirectMessage.sessionCode(ctx, params.TargetID); err == nil {
+				params.ChatCode = code
+			} else {
+				s.client.logger.WithError(err).Debugf("创建私聊会话失败，改用target_id发送")
+			}
 		}
 		if params.ChatCode != "" {
 			requestParams["chat_code"] = params.ChatCode
+		} else {
+			requestParams["target_id"] = params.TargetID
 		}
 	} else {
 		endpoint = "message/create"
@@ -43,6 +88,18 @@ func (s *MessageService) SendMessage(ctx context.Context, params SendMessagePara
 		requestParams["target_id"] = params.TargetID
 	}
 
+	// 模板消息的 content 为模板参数 JSON
+	if params.TemplateData != nil {
+		if params.TemplateID == "" {
+			return nil, NewValidationError("template_id", "设置模板参数时必须指定模板ID")
+		}
+		data, err := json.Marshal(params.TemplateData)
+		if err != nil {
+			return nil, fmt.Errorf("序列化模板参数失败: %w", err)
+		}
+		params.Content = string(data)
+	}
+
 	// 设置消息内容和类型
 	if params.Content == "" {
 		return nil, fmt.Errorf("消息内容不能为空")
@@ -58,10 +115,18 @@ func (s *MessageService) SendMessage(ctx context.Context, params SendMessagePara
 			msgType = MessageTypeKMD
 		}
 	}
-	if msgType == MessageTypeCard {
-		if err := validateCardContent(params.Content); err != nil {
+	if err := validateMessageType(msgType); err != nil {
+		return nil, err
+	}
+	if params.TemplateID != "" {
+		if err := validateTemplateContent(params.Content); err != nil {
 			return nil, err
 		}
+	} else if err := ValidateMessageContent(msgType, params.Content); err != nil {
+		return nil, err
+	} else if s.client.emojiShortcodes && (msgType == MessageTypeText || msgType == MessageTypeKMD) {
+		params.Content = ReplaceEmojiShortcodes(params.Content)
+		requestParams["content"] = params.Content
 	}
 	requestParams["type"] = msgType
 
@@ -69,9 +134,7 @@ func (s *MessageService) SendMessage(ctx context.Context, params SendMessagePara
 	if params.Quote != "" {
 		requestParams["quote"] = params.Quote
 	}
-	if params.Nonce != "" {
-		requestParams["nonce"] = params.Nonce
-	}
+	requestParams["nonce"] = params.Nonce
 	if params.TempTargetID != "" {
 		requestParams["temp_target_id"] = params.TempTargetID
 	}
@@ -96,14 +159,58 @@ func (s *MessageService) SendMessage(ctx context.Context, params SendMessagePara
 		return nil, fmt.Errorf("解析消息失败: %w", err)
 	}
 
+	nonce := created.Nonce
+	if nonce == "" {
+		nonce = params.Nonce
+	}
+	chatCode := ""
+	if scope == "private" {
+		chatCode = params.ChatCode
+	}
+
 	return &Message{
 		ID:       created.MsgID,
 		Type:     msgType,
 		Content:  params.Content,
 		CreateAt: created.MsgTimestamp,
+		Nonce:    nonce,
+		TargetID: params.TargetID,
+		ChatCode: chatCode,
 	}, nil
 }
 
+// SendImage 上传图片并以图片消息发送到频道
+func (s *MessageService) SendImage(ctx context.Context, targetID string, img io.Reader, filename string) (*Message, error) {
+	return s.sendAsset(ctx, targetID, img, filename, MessageTypeImage, "图片")
+}
+
+// SendFile 上传文件并以文件消息发送到频道
+func (s *MessageService) SendFile(ctx context.Context, targetID string, file io.Reader, filename string) (*Message, error) {
+	return s.sendAsset(ctx, targetID, file, filename, MessageTypeFile, "文件")
+}
+
+// SendVideo 上传视频并以视频消息发送到频道
+func (s *MessageService) SendVideo(ctx context.Context, targetID string, video io.Reader, filename string) (*Message, error) {
+	return s.sendAsset(ctx, targetID, video, filename, MessageTypeVideo, "视频")
+}
+
+// sendAsset 先上传素材拿到URL，再按指定消息类型发送
+func (s *MessageService) sendAsset(ctx context.Context, targetID string, r io.Reader, filename string, msgType int, kind string) (*Message, error) {
+	if targetID == "" {
+		return nil, fmt.Errorf("频道消息目标ID不能为空")
+	}
+	assetURL, err := s.client.Asset.CreateAsset(ctx, r, filename)
+	if err != nil {
+		return nil, fmt.Errorf("上传%s失败: %w", kind, err)
+	}
+
+	return s.SendMessage(ctx, SendMessageParams{
+		TargetID: targetID,
+		Content:  assetURL,
+		MsgType:  msgType,
+	})
+}
+
 // GetMessageList 获取消息列表
 func (s *MessageService) GetMessageList(ctx context.Context, targetID string, params GetMessageListParams) (*ListMessagesResponse, error) {
 	var endpoint string
@@ -142,7 +249,10 @@ func (s *MessageService) GetMessageList(ctx context.Context, targetID string, pa
 		query["pin"] = strconv.Itoa(params.Pin)
 	}
 	if params.Flag != "" {
-		query["flag"] = params.Flag
+		if !params.Flag.valid() {
+			return nil, fmt.Errorf("无效的消息列表查询方向: %s", params.Flag)
+		}
+		query["flag"] = string(params.Flag)
 	}
 	if params.PageSize > 0 && params.PageSize <= 100 {
 		query["page_size"] = strconv.Itoa(params.PageSize)
@@ -161,6 +271,149 @@ func (s *MessageService) GetMessageList(ctx context.Context, targetID string, pa
 	return &result, nil
 }
 
+// GetMessagesBefore 获取频道中指定消息之前的最多 limit 条消息，limit 为0时使用默认值50
+func (s *MessageService) GetMessagesBefore(ctx context.Context, targetID, msgID string, limit int) ([]Message, error) {
+	return s.getMessagesAt(ctx, targetID, GetMessageListParams{}, FlagBefore, msgID, limit)
+}
+
+// GetMessagesAfter 获取频道中指定消息之后的最多 limit 条消息，limit 为0时使用默认值50
+func (s *MessageService) GetMessagesAfter(ctx context.Context, targetID, msgID string, limit int) ([]Message, error) {
+	return s.getMessagesAt(ctx, targetID, GetMessageListParams{}, FlagAfter, msgID, limit)
+}
+
+// GetMessagesAround 获取频道中指定消息前后的最多 limit 条消息，limit 为0时使用默认值50
+func (s *MessageService) GetMessagesAround(ctx context.Context, targetID, msgID string, limit int) ([]Message, error) {
+	return s.getMessagesAt(ctx, targetID, GetMessageListParams{}, FlagAround, msgID, limit)
+}
+
+// getMessagesAt 以 msgID 为参考按 flag 方向查询一页消息
+func (s *MessageService) getMessagesAt(ctx context.Context, targetID string, params GetMessageListParams, flag MessageListFlag, msgID string, limit int) ([]Message, error) {
+	if msgID == "" {
+		return nil, fmt.Errorf("参考消息ID不能为空")
+	}
+	if limit < 0 || limit > 100 {
+		return nil, fmt.Errorf("消息数量必须在1-100之间")
+	}
+
+	params.MsgID = msgID
+	params.Flag = flag
+	params.PageSize = limit
+
+	result, err := s.GetMessageList(ctx, targetID, params)
+	if err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// MessageIterator 消息列表迭代器
+// 以上一页最早的一条消息为 before 游标持续向前翻页，直到没有更多消息
+type MessageIterator struct {
+	service  *MessageService
+	targetID string
+	params   GetMessageListParams
+	buffer   []Message
+	done     bool
+}
+
+// IterateMessages 创建消息列表迭代器
+// params.MsgID 为空时从最新消息开始；PageSize 超过100时按100处理
+func (s *MessageService) IterateMessages(ctx context.Context, targetID string, params GetMessageListParams) *MessageIterator {
+	if params.PageSize <= 0 {
+		params.PageSize = 50
+	} else if params.PageSize > 100 {
+		params.PageSize = 100
+	}
+	if params.MsgID != "" {
+		params.Flag = FlagBefore
+	}
+
+	return &MessageIterator{
+		service:  s,
+		targetID: targetID,
+		params:   params,
+	}
+}
+
+// Next 返回下一条消息；没有更多消息时返回 (nil, false, nil)，出错时返回错误
+func (it *MessageIterator) Next(ctx context.Context) (*Message, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, false, err
+	}
+
+	if len(it.buffer) == 0 {
+		if it.done {
+			return nil, false, nil
+		}
+		if err := it.fetch(ctx); err != nil {
+			return nil, false, err
+		}
+		if len(it.buffer) == 0 {
+			return nil, false, nil
+		}
+	}
+
+	msg := it.buffer[0]
+	it.buffer = it.buffer[1:]
+	return &msg, true, nil
+}
+
+// fetch 拉取下一页并推进游标
+func (it *MessageIterator) fetch(ctx context.Context) error {
+	result, err := it.service.GetMessageList(ctx, it.targetID, it.params)
+	if err != nil {
+		return fmt.Errorf("获取消息列表失败: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		it.done = true
+		return nil
+	}
+
+	// 不依赖接口返回顺序，取创建时间最早的消息作为下一页游标
+	oldest := result.Items[0]
+	for _, item := range result.Items[1:] {
+		if item.CreateAt < oldest.CreateAt {
+			oldest = item
+		}
+	}
+
+	if len(result.Items) < it.params.PageSize || oldest.ID == "" || oldest.ID == it.params.MsgID {
+		it.done = true
+	}
+
+	it.params.MsgID = oldest.ID
+	it.params.Flag = FlagBefore
+	it.buffer = result.Items
+	return nil
+}
+
+// GetPinnedMessages 获取频道当前全部置顶消息
+// 通过 message/list 的 pin=1 按页向前翻取并按消息ID去重
+func (s *MessageService) GetPinnedMessages(ctx context.Context, channelID string) ([]Message, error) {
+	if channelID == "" {
+		return nil, fmt.Errorf("频道ID不能为空")
+	}
+
+	it := s.IterateMessages(ctx, channelID, GetMessageListParams{Pin: 1, PageSize: 100})
+	var messages []Message
+	seen := make(map[string]struct{})
+	for {
+		msg, ok, err := it.Next(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("获取置顶消息失败: %w", err)
+		}
+		if !ok {
+			return messages, nil
+		}
+		if _, dup := seen[msg.ID]; dup {
+			continue
+		}
+		seen[msg.ID] = struct{}{}
+		messages = append(messages, *msg)
+	}
+}
+
 // GetMessage 获取消息详情
 func (s *MessageService) GetMessage(ctx context.Context, msgID string) (*Message, error) {
 	if msgID == "" {
@@ -252,6 +505,30 @@ func (s *MessageService) UpdateMessage(ctx context.Context, msgID, content strin
 	}, nil
 }
 
+// SendEphemeralMessage 在频道中发送仅指定用户可见的临时消息
+// 临时消息不会进入频道历史，用户重启客户端后即消失；更新时必须通过 UpdateEphemeralMessage
+// 携带同一用户ID，无法被编辑为所有人可见的普通消息
+func (s *MessageService) SendEphemeralMessage(ctx context.Context, channelID, userID, content string, msgType int) (*Message, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("临时消息的用户ID不能为空")
+	}
+
+	return s.SendMessage(ctx, SendMessageParams{
+		TargetID:     channelID,
+		Content:      content,
+		MsgType:      msgType,
+		TempTargetID: userID,
+	})
+}
+
+// UpdateEphemeralMessage 更新仅指定用户可见的临时消息，userID 必须与发送时一致
+func (s *MessageService) UpdateEphemeralMessage(ctx context.Context, msgID, userID, content string) (*Message, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("临时消息的用户ID不能为空")
+	}
+	return s.UpdateMessage(ctx, msgID, content, "", userID)
+}
+
 // UpdateDirectMessage 更新私聊消息（支持 KMarkdown 与 CardMessage）
 func (s *MessageService) UpdateDirectMessage(ctx context.Context, msgID, content, quote string) error {
 	if msgID == "" {
@@ -287,6 +564,52 @@ func (s *MessageService) DeleteMessage(ctx context.Context, msgID string) error
 	return err
 }
 
+// BulkDeleteMessages 批量删除频道消息
+// 按 WithBulkConcurrency 配置的并发度（默认5）逐条调用 message/delete，单条失败不影响其余消息；
+// 遇到限流时由客户端的重试策略（WithRetryConfig）对该条消息退避重试。返回成功删除的消息ID和失败原因映射
+func (s *MessageService) BulkDeleteMessages(ctx context.Context, msgIDs []string) (deleted []string, failed map[string]error) {
+	failed = make(map[string]error)
+
+	concurrency := s.client.bulkConcurrency
+	if concurrency <= 0 {
+		concurrency = 5
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for _, msgID := range msgIDs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			mu.Lock()
+			failed[msgID] = ctx.Err()
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			err := s.DeleteMessage(ctx, id)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed[id] = err
+				return
+			}
+			deleted = append(deleted, id)
+		}(msgID)
+	}
+
+	wg.Wait()
+	return deleted, failed
+}
+
 // DeleteDirectMessage 删除私聊消息
 func (s *MessageService) DeleteDirectMessage(ctx context.Context, msgID string) error {
 	if msgID == "" {
@@ -337,6 +660,85 @@ func (s *MessageService) AddDirectReaction(ctx context.Context, msgID, emoji str
 	return err
 }
 
+// DefaultReactionInterval 批量添加或清除回应时相邻两次请求的间隔
+const DefaultReactionInterval = 200 * time.Millisecond
+
+// ReactionFailure 单个回应操作失败的原因
+type ReactionFailure struct {
+	Emoji  string
+	UserID string // 清除回应时对应的用户，添加回应时为空
+	Err    error
+}
+
+// ReactionsError 批量操作回应时部分失败的聚合错误，Failures 按操作顺序排列
+type ReactionsError struct {
+	Failures []ReactionFailure
+}
+
+// Error 实现 error 接口
+func (e *ReactionsError) Error() string {
+	parts := make([]string, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		if f.UserID != "" {
+			parts = append(parts, fmt.Sprintf("%s(用户 %s): %v", f.Emoji, f.UserID, f.Err))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %v", f.Emoji, f.Err))
+	}
+	return fmt.Sprintf("%d 个回应操作失败: %s", len(e.Failures), strings.Join(parts, "; "))
+}
+
+// Unwrap 返回各表情的错误，便于 errors.Is / errors.As 判断
+func (e *ReactionsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failures))
+	for _, f := range e.Failures {
+		errs = append(errs, f.Err)
+	}
+	return errs
+}
+
+// AddReactions 按顺序为频道消息添加多个回应，每次之间间隔 DefaultReactionInterval 以避免触发限流
+// 单个表情失败不会中断后续表情，全部完成后以 *ReactionsError 返回失败的表情
+func (s *MessageService) AddReactions(ctx context.Context, msgID string, emojis []string) error {
+	return s.addReactions(ctx, msgID, emojis, s.AddReaction)
+}
+
+// AddDirectReactions 按顺序为私聊消息添加多个回应，行为同 AddReactions
+func (s *MessageService) AddDirectReactions(ctx context.Context, msgID string, emojis []string) error {
+	return s.addReactions(ctx, msgID, emojis, s.AddDirectReaction)
+}
+
+// addReactions 逐个添加回应并收集失败的表情，ctx 结束时剩余表情均记为失败
+func (s *MessageService) addReactions(ctx context.Context, msgID string, emojis []string, add func(context.Context, string, string) error) error {
+	if msgID == "" {
+		return fmt.Errorf("消息ID不能为空")
+	}
+
+	var failures []ReactionFailure
+	for i, emoji := range emojis {
+		if i > 0 {
+			timer := time.NewTimer(DefaultReactionInterval)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				for _, rest := range emojis[i:] {
+					failures = append(failures, ReactionFailure{Emoji: rest, Err: ctx.Err()})
+				}
+				return &ReactionsError{Failures: failures}
+			}
+		}
+		if err := add(ctx, msgID, emoji); err != nil {
+			failures = append(failures, ReactionFailure{Emoji: emoji, Err: err})
+		}
+	}
+
+	if len(failures) > 0 {
+		return &ReactionsError{Failures: failures}
+	}
+	return nil
+}
+
 // DeleteReaction 删除回应
 func (s *MessageService) DeleteReaction(ctx context.Context, msgID, emoji, userID string) error {
 	if msgID == "" {
@@ -359,6 +761,73 @@ func (s *MessageService) DeleteReaction(ctx context.Context, msgID, emoji, userI
 	return err
 }
 
+// ClearReactions 清空频道消息上所有用户的全部回应
+// 官方没有单独的接口，这里先获取消息的回应列表，再翻页取出每个表情的回应用户逐个删除，
+// 相邻两次删除间隔 DefaultReactionInterval；删除他人回应需要管理消息权限，权限不足时立即返回
+// 其他失败不会中断后续删除，全部完成后以 *ReactionsError 返回
+func (s *MessageService) ClearReactions(ctx context.Context, msgID string) error {
+	msg, err := s.GetMessage(ctx, msgID)
+	if err != nil {
+		return fmt.Errorf("获取消息回应失败: %w", err)
+	}
+	return s.clearReactions(ctx, msgID, msg.Reactions, s.GetAllReactionUsers, s.DeleteReaction)
+}
+
+// ClearDirectReactions 清空私聊消息上的全部回应，行为同 ClearReactions
+// 私聊消息详情接口需要 chat_code，回应列表与删除接口只需要消息ID
+func (s *MessageService) ClearDirectReactions(ctx context.Context, chatCode, msgID string) error {
+	msg, err := s.GetDirectMessage(ctx, chatCode, msgID)
+	if err != nil {
+		return fmt.Errorf("获取私聊消息回应失败: %w", err)
+	}
+	return s.clearReactions(ctx, msgID, msg.Reactions, s.GetAllDirectReactionUsers, s.DeleteDirectReaction)
+}
+
+// clearReactions 逐个表情取出全部回应用户并删除，权限不足时立即返回，其余失败聚合为 *ReactionsError
+func (s *MessageService) clearReactions(ctx context.Context, msgID string, reactions []Reaction, listUsers func(context.Context, string, string) ([]User, error), deleteReaction func(context.Context, string, string, string) error) error {
+	var failures []ReactionFailure
+	first := true
+	for _, reaction := range reactions {
+		emoji := reaction.Emoji.ID
+		if emoji == "" {
+			continue
+		}
+		users, err := listUsers(ctx, msgID, emoji)
+		if err != nil {
+			if errors.Is(err, ErrForbidden) {
+				return fmt.Errorf("清除回应权限不足: %w", err)
+			}
+			failures = append(failures, ReactionFailure{Emoji: emoji, Err: err})
+			continue
+		}
+
+		for _, user := range users {
+			if !first {
+				timer := time.NewTimer(DefaultReactionInterval)
+				select {
+				case <-timer.C:
+				case <-ctx.Done():
+					timer.Stop()
+					return ctx.Err()
+				}
+			}
+			first = false
+
+			if err := deleteReaction(ctx, msgID, emoji, user.ID); err != nil {
+				if errors.Is(err, ErrForbidden) {
+					return fmt.Errorf("清除回应权限不足: %w", err)
+				}
+				failures = append(failures, ReactionFailure{Emoji: emoji, UserID: user.ID, Err: err})
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return &ReactionsError{Failures: failures}
+	}
+	return nil
+}
+
 // DeleteDirectReaction 删除私聊消息回应
 func (s *MessageService) DeleteDirectReaction(ctx context.Context, msgID, emoji, userID string) error {
 	if msgID == "" {
@@ -382,33 +851,76 @@ func (s *MessageService) DeleteDirectReaction(ctx context.Context, msgID, emoji,
 
 // GetReactionUserList 获取回应用户列表
 func (s *MessageService) GetReactionUserList(ctx context.Context, msgID, emoji string) ([]User, error) {
-	if msgID == "" {
-		return nil, fmt.Errorf("消息ID不能为空")
-	}
-	if emoji == "" {
-		return nil, fmt.Errorf("表情不能为空")
+	page, err := s.reactionUserPage(ctx, "message/reaction-list", msgID, emoji, 0, 0)
+	if err != nil {
+		return nil, err
 	}
+	return page.Items, nil
+}
 
-	query := map[string]string{
-		"msg_id": msgID,
-		"emoji":  emoji,
-	}
+// GetReactionUserListPage 按页获取回应用户列表，page/pageSize 小于等于0时不传
+// 接口返回裸数组时 Meta 为空，表示已返回全部用户
+func (s *MessageService) GetReactionUserListPage(ctx context.Context, msgID, emoji string, page, pageSize int) (*Page[User], error) {
+	return s.reactionUserPage(ctx, "message/reaction-list", msgID, emoji, page, pageSize)
+}
+
+// GetAllReactionUsers 自动翻页获取全部回应用户，按用户ID去重
+func (s *MessageService) GetAllReactionUsers(ctx context.Context, msgID, emoji string) ([]User, error) {
+	return s.allReactionUsers(ctx, "message/reaction-list", msgID, emoji)
+}
 
-	resp, err := s.client.Get(ctx, "message/reaction-list", query)
+// GetDirectReactionUserList 获取私聊消息回应用户列表
+func (s *MessageService) GetDirectReactionUserList(ctx context.Context, msgID, emoji string) ([]User, error) {
+	page, err := s.reactionUserPage(ctx, "direct-message/reaction-list", msgID, emoji, 0, 0)
 	if err != nil {
 		return nil, err
 	}
+	return page.Items, nil
+}
+
+// GetDirectReactionUserListPage 按页获取私聊消息回应用户列表，page/pageSize 小于等于0时不传
+func (s *MessageService) GetDirectReactionUserListPage(ctx context.Context, msgID, emoji string, page, pageSize int) (*Page[User], error) {
+	return s.reactionUserPage(ctx, "direct-message/reaction-list", msgID, emoji, page, pageSize)
+}
 
+// GetAllDirectReactionUsers 自动翻页获取私聊消息的全部回应用户，按用户ID去重
+func (s *MessageService) GetAllDirectReactionUsers(ctx context.Context, msgID, emoji string) ([]User, error) {
+	return s.allReactionUsers(ctx, "direct-message/reaction-list", msgID, emoji)
+}
+
+// reactionUserPageSize 自动翻页时每页请求的用户数
+const reactionUserPageSize = 50
+
+// allReactionUsers 逐页拉取回应用户直到末页，按用户ID去重
+func (s *MessageService) allReactionUsers(ctx context.Context, endpoint, msgID, emoji string) ([]User, error) {
 	var users []User
-	if err := json.Unmarshal(resp.Data, &users); err != nil {
-		return nil, fmt.Errorf("解析用户列表失败: %w", err)
-	}
+	seen := make(map[string]struct{})
+
+	for page := 1; ; page++ {
+		result, err := s.reactionUserPage(ctx, endpoint, msgID, emoji, page, reactionUserPageSize)
+		if err != nil {
+			return users, fmt.Errorf("获取第%d页回应用户失败: %w", page, err)
+		}
 
-	return users, nil
+		added := 0
+		for _, u := range result.Items {
+			if _, ok := seen[u.ID]; ok {
+				continue
+			}
+			seen[u.ID] = struct{}{}
+			users = append(users, u)
+			added++
+		}
+
+		// 裸数组没有分页信息；没有新用户时也停止，避免接口忽略分页参数导致死循环
+		if !result.HasNext() || added == 0 {
+			return users, nil
+		}
+	}
 }
 
-// GetDirectReactionUserList 获取私聊消息回应用户列表
-func (s *MessageService) GetDirectReactionUserList(ctx context.Context, msgID, emoji string) ([]User, error) {
+// reactionUserPage 请求回应用户列表，兼容裸数组与 items 包裹两种返回结构
+func (s *MessageService) reactionUserPage(ctx context.Context, endpoint, msgID, emoji string, page, pageSize int) (*Page[User], error) {
 	if msgID == "" {
 		return nil, fmt.Errorf("消息ID不能为空")
 	}
@@ -420,18 +932,30 @@ func (s *MessageService) GetDirectReactionUserList(ctx context.Context, msgID, e
 		"msg_id": msgID,
 		"emoji":  emoji,
 	}
+	if page > 0 {
+		query["page"] = strconv.Itoa(page)
+	}
+	if pageSize > 0 {
+		query["page_size"] = strconv.Itoa(pageSize)
+	}
 
-	resp, err := s.client.Get(ctx, "direct-message/reaction-list", query)
+	resp, err := s.client.Get(ctx, endpoint, query)
 	if err != nil {
 		return nil, err
 	}
 
-	var users []User
-	if err := json.Unmarshal(resp.Data, &users); err != nil {
+	var result Page[User]
+	data := bytes.TrimSpace(resp.Data)
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &result.Items); err != nil {
+			return nil, fmt.Errorf("解析用户列表失败: %w", err)
+		}
+		return &result, nil
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
 		return nil, fmt.Errorf("解析用户列表失败: %w", err)
 	}
-
-	return users, nil
+	return &result, nil
 }
 
 // CheckCard 检查卡片消息格式
@@ -465,21 +989,51 @@ type SendMessageParams struct {
 	Content      string `json:"content"`                  // 消息内容
 	MsgType      int    `json:"msg_type,omitempty"`       // 消息类型（1文本，9KMarkdown，10卡片）
 	Quote        string `json:"quote,omitempty"`          // 引用消息ID
-	Nonce        string `json:"nonce,omitempty"`          // 随机字符串，防重复
+	Nonce        string `json:"nonce,omitempty"`          // 随机字符串，防重复（为空时自动生成）
 	TempTargetID string `json:"temp_target_id,omitempty"` // 临时目标ID
 	TemplateID   string `json:"template_id,omitempty"`    // 模板ID
 	ReplyMsgID   string `json:"reply_msg_id,omitempty"`   // 回复消息ID（用于配额折扣）
+
+	// TemplateData 模板参数，发送时序列化为 JSON 作为 Content（需同时设置 TemplateID）
+	TemplateData interface{} `json:"-"`
+}
+
+// WithTemplateData 设置模板参数，发送时自动序列化为 JSON 作为 Content
+func (p SendMessageParams) WithTemplateData(data interface{}) SendMessageParams {
+	p.TemplateData = data
+	return p
 }
 
 // SendCardMessage 发送卡片消息
 func (s *MessageService) SendCardMessage(ctx context.Context, params SendMessageParams) (*Message, error) {
-	if err := validateCardContent(params.Content); err != nil {
-		return nil, err
+	// 模板卡片消息的 content 为模板参数，由 sendMessage 校验
+	if params.TemplateID == "" {
+		if err := validateCardContent(params.Content); err != nil {
+			return nil, err
+		}
 	}
 	params.MsgType = MessageTypeCard
 	return s.SendMessage(ctx, params)
 }
 
+// SendCards 把若干构建好的卡片作为一条卡片消息发送到频道
+// 卡片数量不能超过 MaxCardsPerMessage，发送前按嵌套规则在本地校验卡片结构
+func (s *MessageService) SendCards(ctx context.Context, targetID string, cards ...*Card) (*Message, error) {
+	if targetID == "" {
+		return nil, fmt.Errorf("频道消息目标ID不能为空")
+	}
+
+	content, err := NewCardMessage(cards...).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.SendCardMessage(ctx, SendMessageParams{
+		TargetID: targetID,
+		Content:  content,
+	})
+}
+
 func normalizeMessageScope(scope string) (string, error) {
 	switch strings.ToLower(strings.TrimSpace(scope)) {
 	case "", "channel", "guild":
@@ -491,25 +1045,101 @@ func normalizeMessageScope(scope string) (string, error) {
 	}
 }
 
+// 消息内容的本地校验阈值
+const (
+	// MaxMessageContentLength 文本与 KMarkdown 消息的最大字符数
+	MaxMessageContentLength = 20000
+	// MaxCardsPerMessage 单条卡片消息最多包含的 card 数
+	MaxCardsPerMessage = 5
+	// MaxCardModules 单条卡片消息中所有 card 的 module 总数上限
+	MaxCardModules = 50
+)
+
+// ValidateMessageContent 按消息类型在本地预检内容，超出 KOOK 限制时返回 *ValidationError
+func ValidateMessageContent(msgType int, content string) error {
+	switch msgType {
+	case MessageTypeCard:
+		return validateCardContent(content)
+	case MessageTypeText, MessageTypeKMD:
+		if n := utf8.RuneCountInString(content); n > MaxMessageContentLength {
+			return NewValidationErrorWithValue("content",
+				fmt.Sprintf("消息内容超过 %d 字符", MaxMessageContentLength), strconv.Itoa(n))
+		}
+	}
+	return nil
+}
+
+// validateMessageType 校验消息类型是否支持主动发送
+func validateMessageType(msgType int) error {
+	if !MessageType(msgType).Sendable() {
+		return NewValidationErrorWithValue("type",
+			fmt.Sprintf("不支持发送%s", MessageType(msgType)), strconv.Itoa(msgType))
+	}
+	return nil
+}
+
+// validateTemplateContent 校验模板消息的 content 是否为 JSON 对象
+func validateTemplateContent(content string) error {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &data); err != nil || data == nil {
+		return NewValidationErrorWithValue("content", "模板消息 content 必须是 JSON 对象", content)
+	}
+	return nil
+}
+
 func validateCardContent(content string) error {
-	var cards []json.RawMessage
+	var cards []struct {
+		Modules []json.RawMessage `json:"modules"`
+	}
 	if err := json.Unmarshal([]byte(content), &cards); err != nil {
 		return fmt.Errorf("卡片消息 content 必须是 JSON 数组字符串: %w", err)
 	}
 	if len(cards) == 0 {
 		return fmt.Errorf("卡片消息至少包含一个 card")
 	}
+	if len(cards) > MaxCardsPerMessage {
+		return NewValidationErrorWithValue("content",
+			fmt.Sprintf("卡片消息最多包含 %d 个 card", MaxCardsPerMessage), strconv.Itoa(len(cards)))
+	}
+
+	modules := 0
+	for _, card := range cards {
+		modules += len(card.Modules)
+	}
+	if modules > MaxCardModules {
+		return NewValidationErrorWithValue("content",
+			fmt.Sprintf("卡片消息的 module 总数不能超过 %d", MaxCardModules), strconv.Itoa(modules))
+	}
 	return nil
 }
 
+// MessageListFlag 消息列表相对参考消息的查询方向
+type MessageListFlag string
+
+// 消息列表查询方向
+const (
+	FlagBefore MessageListFlag = "before" // 参考消息之前的消息
+	FlagAround MessageListFlag = "around" // 参考消息前后的消息
+	FlagAfter  MessageListFlag = "after"  // 参考消息之后的消息
+)
+
+// valid 判断查询方向是否合法
+func (f MessageListFlag) valid() bool {
+	switch f {
+	case FlagBefore, FlagAround, FlagAfter:
+		return true
+	}
+	return false
+}
+
 // GetMessageListParams 获取消息列表参数
 type GetMessageListParams struct {
-	Type     string `json:"type,omitempty"`      // 消息类型：private, channel
-	ChatCode string `json:"chat_code,omitempty"` // 私聊会话Code（私聊可选）
-	MsgID    string `json:"msg_id,omitempty"`    // 参考消息ID
-	Pin      int    `json:"pin,omitempty"`       // 只看置顶消息：0否，1是
-	Flag     string `json:"flag,omitempty"`      // 查询模式：before, around, after
-	PageSize int    `json:"page_size,omitempty"` // 返回数量，默认50，最大100
+	Type     string          `json:"type,omitempty"`      // 消息类型：private, channel
+	ChatCode string          `json:"chat_code,omitempty"` // 私聊会话Code（私聊可选）
+	MsgID    string          `json:"msg_id,omitempty"`    // 参考消息ID
+	Pin      int             `json:"pin,omitempty"`       // 只看置顶消息：0否，1是
+	Flag     MessageListFlag `json:"flag,omitempty"`      // 查询模式：FlagBefore, FlagAround, FlagAfter
+	PageSize int             `json:"page_size,omitempty"` // 返回数量，默认50，最大100
 }
 
 // ListMessagesResponse 消息列表响应
@@ -519,17 +1149,33 @@ type ListMessagesResponse struct {
 
 // CheckCardResponse 检查卡片响应
 type CheckCardResponse struct {
-	Mention struct {
-		Mentions     []string      `json:"mentions"`
-		MentionRoles []string      `json:"mentionRoles"`
-		MentionAll   bool          `json:"mentionAll"`
-		MentionHere  bool          `json:"mentionHere"`
-		MentionPart  []interface{} `json:"mentionPart"`
-		NavChannels  []interface{} `json:"navChannels"`
-		ChannelPart  []interface{} `json:"channelPart"`
-		GuildEmojis  []interface{} `json:"guildEmojis"`
-	} `json:"mention"`
-	Content string `json:"content"`
+	Mention CardMention `json:"mention"`
+	Content string      `json:"content"`
+}
+
+// CardMention 卡片中解析出的提及信息
+type CardMention struct {
+	Mentions     []string      `json:"mentions"`
+	MentionRoles []string      `json:"mentionRoles"`
+	MentionAll   bool          `json:"mentionAll"`
+	MentionHere  bool          `json:"mentionHere"`
+	MentionPart  []MentionPart `json:"mentionPart"` // 被提及用户的详情
+	NavChannels  []NavChannel  `json:"navChannels"` // 卡片中引用的频道
+	ChannelPart  []ChannelPart `json:"channelPart"` // 被提及频道的详情
+	GuildEmojis  []GuildEmoji  `json:"guildEmojis"` // 卡片中使用的服务器表情
+}
+
+// NavChannel 卡片中引用的频道
+type NavChannel struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ChannelPart 被提及频道的信息
+type ChannelPart struct {
+	ID      string `json:"id"`
+	GuildID string `json:"guild_id"`
+	Name    string `json:"name"`
 }
 
 // PinMessage 置顶消息
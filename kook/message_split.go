@@ -0,0 +1,280 @@
+package kook
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// LongMessageOption 长消息分段发送选项
+type LongMessageOption func(*longMessageOptions)
+
+type longMessageOptions struct {
+	maxLength int
+}
+
+// WithMaxSegmentLength 设置每段消息的最大字符数，默认（以及不大于 0 或超过上限时）为 MaxMessageContentLength
+func WithMaxSegmentLength(n int) LongMessageOption {
+	return func(o *longMessageOptions) {
+		o.maxLength = n
+	}
+}
+
+// SendLongMessage 把超长的文本/KMarkdown 内容切分成多条消息依次发送，返回已发送的消息
+// 优先在段落、换行、空白和标点处切分，不会在代码块、行内代码、mention、链接等语法中间断开；
+// 超长的代码块按行拆成多个代码块并补齐 ``` 与语言标记。params 中除 Content 外的字段对每段生效，
+// Quote 只加在第一段上；设置了 Nonce 时每段使用 Nonce-序号 保证幂等。
+// 某一段发送失败时停止发送，返回已发送的消息和错误
+func (s *MessageService) SendLongMessage(ctx context.Context, params SendMessageParams, content string, opts ...LongMessageOption) ([]*Message, error) {
+	if params.MsgType == MessageTypeCard || params.TemplateID != "" {
+		return nil, fmt.Errorf("长消息分段发送只支持文本与 KMarkdown 消息")
+	}
+
+	o := longMessageOptions{maxLength: MaxMessageContentLength}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.maxLength <= 0 || o.maxLength > MaxMessageContentLength {
+		o.maxLength = MaxMessageContentLength
+	}
+
+	segments := SplitMessageContent(content, o.maxLength)
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("消息内容不能为空")
+	}
+
+	nonce := params.Nonce
+	messages := make([]*Message, 0, len(segments))
+	for i, segment := range segments {
+		p := params
+		p.Content = segment
+		if i > 0 {
+			p.Quote = ""
+		}
+		if nonce != "" {
+			p.Nonce = fmt.Sprintf("%s-%d", nonce, i+1)
+		}
+
+		msg, err := s.SendMessage(ctx, p)
+		if err != nil {
+			return messages, fmt.Errorf("发送第 %d/%d 段消息失败: %w", i+1, len(segments), err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// SplitMessageContent 把内容切分为每段不超过 maxLength 字符的多段，切分规则同 SendLongMessage
+// 内容本身不超过 maxLength 时原样返回一段；maxLength 不大于 0 时使用 MaxMessageContentLength
+func SplitMessageContent(content string, maxLength int) []string {
+	if maxLength <= 0 {
+		maxLength = MaxMessageContentLength
+	}
+	if strings.TrimSpace(content) == "" {
+		return nil
+	}
+	if utf8.RuneCountInString(content) <= maxLength {
+		return []string{content}
+	}
+
+	var segments []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if segment := strings.Trim(current.String(), "\n"); strings.TrimSpace(segment) != "" {
+			segments = append(segments, segment)
+		}
+		current.Reset()
+		currentLen = 0
+	}
+
+	for _, block := range splitContentBlocks(content) {
+		n := utf8.RuneCountInString(block.text)
+		if n > maxLength {
+			flush()
+			var parts []string
+			if block.code {
+				parts = splitCodeBlock(block.text, maxLength)
+			} else {
+				parts = splitParagraph(block.text, maxLength)
+			}
+			for _, part := range parts {
+				current.WriteString(part)
+				flush()
+			}
+			continue
+		}
+		if currentLen+n > maxLength {
+			flush()
+		}
+		current.WriteString(block.text)
+		currentLen += n
+	}
+	flush()
+	return segments
+}
+
+// contentBlock 切分的最小单位：一个完整的代码块，或一个带结尾空行的段落
+type contentBlock struct {
+	text string
+	code bool
+}
+
+// splitContentBlocks 把内容拆成代码块与段落，拼接所有块可还原原文
+func splitContentBlocks(content string) []contentBlock {
+	var blocks []contentBlock
+	addText := func(text string) {
+		for text != "" {
+			end := strings.Index(text, "\n\n")
+			if end < 0 {
+				blocks = append(blocks, contentBlock{text: text})
+				return
+			}
+			// 连续的空行都归入前一个段落
+			end += 2
+			for end < len(text) && text[end] == '\n' {
+				end++
+			}
+			blocks = append(blocks, contentBlock{text: text[:end]})
+			text = text[end:]
+		}
+	}
+
+	for content != "" {
+		start := strings.Index(content, "```")
+		if start < 0 {
+			addText(content)
+			break
+		}
+		end := strings.Index(content[start+3:], "```")
+		if end < 0 {
+			// 未闭合的代码块按普通文本处理
+			addText(content)
+			break
+		}
+		end += start + 6
+		addText(content[:start])
+		blocks = append(blocks, contentBlock{text: content[start:end], code: true})
+		content = content[end:]
+	}
+	return blocks
+}
+
+// splitCodeBlock 把超长代码块按行拆成多个完整的代码块，每块都带上原来的语言标记
+func splitCodeBlock(block string, maxLength int) []string {
+	body := strings.TrimSuffix(strings.TrimPrefix(block, "```"), "```")
+	header := ""
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		header = body[:nl]
+		body = body[nl+1:]
+	}
+	body = strings.TrimSuffix(body, "\n")
+
+	openFence, closeFence := "```"+header+"\n", "\n```"
+	budget := maxLength - utf8.RuneCountInString(openFence) - utf8.RuneCountInString(closeFence)
+	if budget <= 0 {
+		// 上限连代码块标记都放不下时只能按普通文本切分
+		return splitParagraph(block, maxLength)
+	}
+
+	var parts []string
+	var current strings.Builder
+	currentLen := 0
+	flush := func() {
+		if currentLen > 0 {
+			parts = append(parts, openFence+strings.TrimSuffix(current.String(), "\n")+closeFence)
+		}
+		current.Reset()
+		currentLen = 0
+	}
+
+	for _, line := range strings.SplitAfter(body, "\n") {
+		n := utf8.RuneCountInString(line)
+		if currentLen+n > budget {
+			flush()
+		}
+		// 单行超过上限时按字符硬切
+		for n > budget {
+			cut := runeOffset(line, budget)
+			current.WriteString(line[:cut])
+			currentLen = budget
+			flush()
+			line = line[cut:]
+			n = utf8.RuneCountInString(line)
+		}
+		current.WriteString(line)
+		currentLen += n
+	}
+	flush()
+	return parts
+}
+
+// kmarkdownSpanPattern 切分时不能断开的行内语法：行内代码、mention/频道/表情/剧透等标签、链接、加粗与删除线
+var kmarkdownSpanPattern = regexp.MustCompile("(?s)`[^`]*`" +
+	`|\(met\).*?\(met\)|\(rol\).*?\(rol\)|\(chn\).*?\(chn\)` +
+	`|\(emj\).*?\(emj\)\[[^\]]*\]|\(font\).*?\(font\)\[[^\]]*\]` +
+	`|\(spl\).*?\(spl\)|\(ins\).*?\(ins\)` +
+	`|\[[^\]\n]*\]\([^)\n]*\)|\*\*.+?\*\*|~~.+?~~`)
+
+// segmentSeparators 切分位置的优先级：换行、空白、标点
+var segmentSeparators = []string{"\n", " \t", "。！？；，.!?;,"}
+
+// splitParagraph 把超长段落切成多段，切分点优先选在换行、空白和标点之后，且不落在行内语法中间
+func splitParagraph(text string, maxLength int) []string {
+	var parts []string
+	for utf8.RuneCountInString(text) > maxLength {
+		cut := paragraphCut(text, maxLength)
+		parts = append(parts, text[:cut])
+		text = text[cut:]
+	}
+	if text != "" {
+		parts = append(parts, text)
+	}
+	return parts
+}
+
+// paragraphCut 返回不超过 maxLength 字符的切分位置（字节偏移）
+func paragraphCut(text string, maxLength int) int {
+	limit := runeOffset(text, maxLength)
+	spans := kmarkdownSpanPattern.FindAllStringIndex(text, -1)
+	inside := func(pos int) (start int, ok bool) {
+		for _, span := range spans {
+			if span[0] < pos && pos < span[1] {
+				return span[0], true
+			}
+		}
+		return 0, false
+	}
+
+	// 只在后半段寻找分隔符，避免切出过短的片段
+	for _, seps := range segmentSeparators {
+		for i := limit; i > limit/2; {
+			r, size := utf8.DecodeLastRuneInString(text[:i])
+			if strings.ContainsRune(seps, r) {
+				if _, ok := inside(i); !ok {
+					return i
+				}
+			}
+			i -= size
+		}
+	}
+
+	// 没有合适的分隔符时在上限处硬切，落在行内语法中间则退到语法开始处
+	if start, ok := inside(limit); ok && start > 0 {
+		return start
+	}
+	return limit
+}
+
+// runeOffset 返回第 n 个字符之后的字节偏移
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
@@ -0,0 +1,41 @@
+package kook
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsCollector 指标收集器，可用于适配 Prometheus 等监控系统
+// 实现需要是并发安全的
+type MetricsCollector interface {
+	// ObserveRequest 记录一次API调用，status 为HTTP状态码，网络错误时为 0
+	ObserveRequest(endpoint string, status int, dur time.Duration)
+	// IncRateLimited 记录一次限流（本地等待或服务端返回限流）
+	IncRateLimited(bucket string)
+	// IncEvent 记录收到的一个事件（Webhook 或 WebSocket）
+	IncEvent(eventType int)
+}
+
+// noopMetrics 默认的空指标收集器
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveRequest(string, int, time.Duration) {}
+func (noopMetrics) IncRateLimited(string)                     {}
+func (noopMetrics) IncEvent(int)                              {}
+
+// WithMetrics 设置指标收集器
+func WithMetrics(collector MetricsCollector) ClientOption {
+	return func(c *Client) {
+		if collector != nil {
+			c.metrics = collector
+		}
+	}
+}
+
+// rateLimitBucketName 返回用于指标的限流 bucket 名称，响应头未给出时使用 endpoint
+func rateLimitBucketName(endpoint string, header http.Header) string {
+	if bucket := header.Get("X-Rate-Limit-Bucket"); bucket != "" {
+		return bucket
+	}
+	return endpoint
+}
@@ -0,0 +1,88 @@
+package kook
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// oggOpusReader 从 Ogg 容器中按包读取 Opus 数据
+// 自动跳过 OpusHead 与 OpusTags 头部包
+type oggOpusReader struct {
+	r       *bufio.Reader
+	pending [][]byte // 当前页中已完整的包
+	partial []byte   // 跨页未完成的包
+}
+
+// newOggOpusReader 创建 Ogg/Opus 读取器
+func newOggOpusReader(r io.Reader) *oggOpusReader {
+	return &oggOpusReader{r: bufio.NewReader(r)}
+}
+
+// ReadPacket 读取下一个 Opus 音频包，读完时返回 io.EOF
+func (o *oggOpusReader) ReadPacket() ([]byte, error) {
+	for {
+		for len(o.pending) > 0 {
+			packet := o.pending[0]
+			o.pending = o.pending[1:]
+			if bytes.HasPrefix(packet, []byte("OpusHead")) || bytes.HasPrefix(packet, []byte("OpusTags")) {
+				continue
+			}
+			return packet, nil
+		}
+
+		if err := o.readPage(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// readPage 读取一个 Ogg 页，并把其中完整的包放入 pending
+func (o *oggOpusReader) readPage() error {
+	var header [27]byte
+	if _, err := io.ReadFull(o.r, header[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return fmt.Errorf("Ogg页头不完整: %w", err)
+		}
+		return err
+	}
+	if string(header[:4]) != "OggS" {
+		return fmt.Errorf("无效的Ogg页: 缺少 OggS 标记")
+	}
+	if header[4] != 0 {
+		return fmt.Errorf("不支持的Ogg版本: %d", header[4])
+	}
+
+	segments := make([]byte, header[26])
+	if _, err := io.ReadFull(o.r, segments); err != nil {
+		return fmt.Errorf("读取Ogg分段表失败: %w", err)
+	}
+
+	size := 0
+	for _, s := range segments {
+		size += int(s)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(o.r, data); err != nil {
+		return fmt.Errorf("读取Ogg页数据失败: %w", err)
+	}
+
+	// header_type 第 0 位表示本页第一个包延续自上一页
+	if header[5]&0x01 == 0 {
+		o.partial = nil
+	}
+
+	offset := 0
+	for _, s := range segments {
+		o.partial = append(o.partial, data[offset:offset+int(s)]...)
+		offset += int(s)
+		// 长度小于 255 的分段表示包结束
+		if s < 255 {
+			o.pending = append(o.pending, o.partial)
+			o.partial = nil
+		}
+	}
+
+	return nil
+}
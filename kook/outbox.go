@@ -0,0 +1,337 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrOutboxClosed 发送队列已关闭
+var ErrOutboxClosed = errors.New("发送队列已关闭")
+
+// OutboxMessage 发送队列中的消息
+type OutboxMessage struct {
+	ID        string            `json:"id"`         // 队列内唯一ID，与消息 nonce 相同
+	Params    SendMessageParams `json:"params"`     // 发送参数（模板参数已在入队时序列化为 Content）
+	Attempts  int               `json:"attempts"`   // 已尝试发送的次数
+	CreatedAt time.Time         `json:"created_at"` // 入队时间
+}
+
+// OutboxStore 发送队列持久化接口，实现落盘后进程重启也不会丢失未发送的消息
+// 实现需要是并发安全的
+type OutboxStore interface {
+	// Save 保存（或更新）一条消息
+	Save(msg *OutboxMessage) error
+	// Remove 删除发送成功或已放弃的消息
+	Remove(id string) error
+	// Load 按入队顺序加载全部未完成的消息，在创建队列时调用
+	Load() ([]*OutboxMessage, error)
+}
+
+// OutboxResultFunc 消息发送结果回调，成功时 err 为 nil，放弃发送时 sent 为 nil
+type OutboxResultFunc func(msg *OutboxMessage, sent *Message, err error)
+
+// OutboxOption 发送队列配置选项
+type OutboxOption func(*OutboxQueue)
+
+// WithOutboxStore 设置持久化存储，默认只保存在内存中
+func WithOutboxStore(store OutboxStore) OutboxOption {
+	return func(q *OutboxQueue) {
+		q.store = store
+	}
+}
+
+// WithOutboxRetry 设置发送失败后的重试策略，MaxRetries 为放弃前的最大重试次数
+func WithOutboxRetry(config *RetryConfig) OutboxOption {
+	return func(q *OutboxQueue) {
+		if config != nil {
+			q.retry = config
+		}
+	}
+}
+
+// WithOutboxCallback 设置发送结果回调，每条消息发送成功或放弃时调用一次
+func WithOutboxCallback(fn OutboxResultFunc) OutboxOption {
+	return func(q *OutboxQueue) {
+		q.onResult = fn
+	}
+}
+
+// defaultOutboxRetryConfig 发送队列的默认重试策略
+func defaultOutboxRetryConfig() *RetryConfig {
+	return &RetryConfig{
+		MaxRetries:    10,
+		InitialDelay:  2 * time.Second,
+		MaxDelay:      5 * time.Minute,
+		BackoffFactor: 2.0,
+	}
+}
+
+// OutboxQueue 消息发送队列
+// 消息按入队顺序由后台 goroutine 逐条发送，失败时按退避重试，前一条完成（成功或放弃）后才发送下一条
+type OutboxQueue struct {
+	client   *Client
+	store    OutboxStore
+	retry    *RetryConfig
+	onResult OutboxResultFunc
+
+	mu      sync.Mutex
+	pending []*OutboxMessage
+	closed  bool
+	notify  chan struct{}
+
+	// ctx 用于发送请求，只有 Close 超时才会取消，保证正在发送的消息能完成
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	quit      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewOutboxQueue 创建发送队列并启动后台发送
+// 设置了持久化存储时会先加载上次未发送完的消息
+func NewOutboxQueue(client *Client, opts ...OutboxOption) (*OutboxQueue, error) {
+	q := &OutboxQueue{
+		client: client,
+		retry:  defaultOutboxRetryConfig(),
+		notify: make(chan struct{}, 1),
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	if q.store != nil {
+		msgs, err := q.store.Load()
+		if err != nil {
+			return nil, fmt.Errorf("加载发送队列失败: %w", err)
+		}
+		q.pending = append(q.pending, msgs...)
+	}
+
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+	go q.run()
+	if len(q.pending) > 0 {
+		q.signal()
+	}
+	return q, nil
+}
+
+// SendMessageAsync 把消息加入发送队列，返回队列内的消息ID
+// 参数会在入队时做基本校验；Nonce 为空时自动生成，重试时使用同一个 nonce
+func (q *OutboxQueue) SendMessageAsync(params SendMessageParams) (string, error) {
+	if params.TargetID == "" && params.ChatCode == "" {
+		return "", fmt.Errorf("目标ID不能为空")
+	}
+	if _, err := normalizeMessageScope(params.Type); err != nil {
+		return "", err
+	}
+	if params.TemplateData != nil {
+		if params.TemplateID == "" {
+			return "", NewValidationError("template_id", "设置模板参数时必须指定模板ID")
+		}
+		data, err := json.Marshal(params.TemplateData)
+		if err != nil {
+			return "", fmt.Errorf("序列化模板参数失败: %w", err)
+		}
+		params.Content = string(data)
+		params.TemplateData = nil
+	}
+	if params.Content == "" {
+		return "", fmt.Errorf("消息内容不能为空")
+	}
+	// 内容不合法时重试也不会成功，入队前先校验
+	if params.MsgType > 0 {
+		if err := validateMessageType(params.MsgType); err != nil {
+			return "", err
+		}
+	}
+	if params.TemplateID != "" {
+		if err := validateTemplateContent(params.Content); err != nil {
+			return "", err
+		}
+	} else if params.MsgType > 0 {
+		if err := ValidateMessageContent(params.MsgType, params.Content); err != nil {
+			return "", err
+		}
+	}
+	if params.Nonce == "" {
+		params.Nonce = newNonce()
+	}
+
+	msg := &OutboxMessage{
+		ID:        params.Nonce,
+		Params:    params,
+		CreatedAt: time.Now(),
+	}
+
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return "", ErrOutboxClosed
+	}
+	if q.store != nil {
+		if err := q.store.Save(msg); err != nil {
+			q.mu.Unlock()
+			return "", fmt.Errorf("保存待发送消息失败: %w", err)
+		}
+	}
+	q.pending = append(q.pending, msg)
+	q.mu.Unlock()
+
+	q.signal()
+	return msg.ID, nil
+}
+
+// Len 返回队列中尚未完成的消息数（包括正在发送的消息）
+func (q *OutboxQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.pending)
+}
+
+// Close 停止接收新消息并等待正在发送的消息完成
+// 尚未发送的消息保留在持久化存储中，下次创建队列时继续发送；ctx 结束时中断正在进行的发送
+func (q *OutboxQueue) Close(ctx context.Context) error {
+	q.closeOnce.Do(func() {
+		q.mu.Lock()
+		q.closed = true
+		q.mu.Unlock()
+		close(q.quit)
+	})
+
+	select {
+	case <-q.done:
+		q.cancel()
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		return ctx.Err()
+	}
+}
+
+// signal 唤醒后台发送 goroutine
+func (q *OutboxQueue) signal() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// head 返回队首消息，队列为空时返回 nil
+func (q *OutboxQueue) head() *OutboxMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.pending) == 0 {
+		return nil
+	}
+	return q.pending[0]
+}
+
+// run 按顺序发送队列中的消息直到队列关闭
+func (q *OutboxQueue) run() {
+	defer close(q.done)
+
+	for {
+		select {
+		case <-q.quit:
+			return
+		default:
+		}
+
+		msg := q.head()
+		if msg == nil {
+			select {
+			case <-q.notify:
+				continue
+			case <-q.quit:
+				return
+			}
+		}
+
+		if !q.deliver(msg) {
+			return
+		}
+	}
+}
+
+// deliver 发送单条消息，失败时按退避重试，返回 false 表示队列已关闭、消息留待下次发送
+func (q *OutboxQueue) deliver(msg *OutboxMessage) bool {
+	for {
+		msg.Attempts++
+		sent, err := q.client.Message.SendMessage(q.ctx, msg.Params)
+		if err == nil {
+			q.finish(msg, sent, nil)
+			return true
+		}
+		if q.ctx.Err() != nil {
+			// 发送被 Close 中断，不计入尝试次数
+			msg.Attempts--
+			return false
+		}
+		if !outboxRetryable(err) || msg.Attempts > q.retry.MaxRetries {
+			q.client.logger.WithError(err).Errorf("发送队列放弃消息 %s，已尝试 %d 次", msg.ID, msg.Attempts)
+			q.finish(msg, nil, err)
+			return true
+		}
+
+		if q.store != nil {
+			if saveErr := q.store.Save(msg); saveErr != nil {
+				q.client.logger.WithError(saveErr).Warnf("更新待发送消息失败: %s", msg.ID)
+			}
+		}
+
+		delay := GetRetryDelay(msg.Attempts-1, q.retry)
+		q.client.logger.Warnf("发送队列消息 %s 发送失败，等待 %v 后重试 (第 %d 次): %v", msg.ID, delay, msg.Attempts, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-q.quit:
+			timer.Stop()
+			return false
+		}
+	}
+}
+
+// finish 把消息移出队列并回调发送结果
+func (q *OutboxQueue) finish(msg *OutboxMessage, sent *Message, err error) {
+	q.mu.Lock()
+	if len(q.pending) > 0 && q.pending[0] == msg {
+		q.pending = q.pending[1:]
+	}
+	q.mu.Unlock()
+
+	if q.store != nil {
+		if removeErr := q.store.Remove(msg.ID); removeErr != nil {
+			q.client.logger.WithError(removeErr).Warnf("删除已完成的消息失败: %s", msg.ID)
+		}
+	}
+
+	if q.onResult == nil {
+		return
+	}
+	defer func() {
+		if rec := recover(); rec != nil {
+			q.client.logger.Errorf("发送队列回调发生panic: %v", rec)
+		}
+	}()
+	q.onResult(msg, sent, err)
+}
+
+// outboxRetryable 判断发送失败是否值得重试
+// 网络错误等一律重试，KOOK 明确拒绝（参数错误、无权限等）的请求重试也不会成功
+func outboxRetryable(err error) bool {
+	var kookErr *KOOKError
+	if errors.As(err, &kookErr) {
+		return kookErr.IsRetryable()
+	}
+	var validationErr *ValidationError
+	return !errors.As(err, &validationErr)
+}
@@ -0,0 +1,131 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Page 通用分页响应：{ items, meta: {page, page_total, page_size, total}, sort }
+type Page[T any] struct {
+	Items []T            `json:"items"`
+	Meta  PaginationMeta `json:"meta"`
+	Sort  map[string]int `json:"sort"`
+}
+
+// HasNext 判断是否还有下一页
+func (p *Page[T]) HasNext() bool {
+	return len(p.Items) > 0 && p.Meta.Page < p.Meta.PageTotal
+}
+
+// getPaged 请求分页列表接口并把结果解析到 out（*Page[T]）
+func (c *Client) getPaged(ctx context.Context, endpoint string, query map[string]string, out interface{}) error {
+	resp, err := c.Get(ctx, endpoint, query)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(resp.Data, out); err != nil {
+		return fmt.Errorf("解析分页数据失败: %w", err)
+	}
+	return nil
+}
+
+// PageIterator 通用分页迭代器，按 page/page_size 自动翻页，根据 meta.page_total 判断末页
+type PageIterator[T any] struct {
+	client   *Client
+	endpoint string
+	query    map[string]string
+	pageSize int
+	page     int
+	buffer   []T
+	done     bool
+	err      error
+	filter   func(*T) bool
+}
+
+// newPageIterator 创建分页迭代器，query 中无需包含 page 与 page_size
+func newPageIterator[T any](client *Client, endpoint string, query map[string]string, pageSize int) *PageIterator[T] {
+	q := make(map[string]string, len(query)+2)
+	for k, v := range query {
+		q[k] = v
+	}
+
+	return &PageIterator[T]{
+		client:   client,
+		endpoint: endpoint,
+		query:    q,
+		pageSize: pageSize,
+		page:     1,
+	}
+}
+
+// newPageIteratorError 创建直接返回错误的迭代器，用于参数校验失败的场景
+func newPageIteratorError[T any](err error) *PageIterator[T] {
+	return &PageIterator[T]{err: err, done: true}
+}
+
+// Next 返回下一项；遍历完毕时返回 (nil, false, nil)，出错时返回错误
+func (it *PageIterator[T]) Next(ctx context.Context) (*T, bool, error) {
+	if it.err != nil {
+		return nil, false, it.err
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, false, err
+		}
+
+		for len(it.buffer) > 0 {
+			item := it.buffer[0]
+			it.buffer = it.buffer[1:]
+			if it.filter != nil && !it.filter(&item) {
+				continue
+			}
+			return &item, true, nil
+		}
+
+		if it.done {
+			return nil, false, nil
+		}
+		if err := it.fetch(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+}
+
+// All 遍历剩余全部项
+func (it *PageIterator[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for {
+		item, ok, err := it.Next(ctx)
+		if err != nil {
+			return items, err
+		}
+		if !ok {
+			return items, nil
+		}
+		items = append(items, *item)
+	}
+}
+
+// fetch 拉取下一页
+func (it *PageIterator[T]) fetch(ctx context.Context) error {
+	it.query["page"] = strconv.Itoa(it.page)
+	if it.pageSize > 0 {
+		it.query["page_size"] = strconv.Itoa(it.pageSize)
+	}
+
+	var page Page[T]
+	if err := it.client.getPaged(ctx, it.endpoint, it.query, &page); err != nil {
+		return fmt.Errorf("获取第%d页失败: %w", it.page, err)
+	}
+
+	// 部分接口不返回 meta，此时以空页作为结束条件
+	if len(page.Items) == 0 || (page.Meta.PageTotal > 0 && it.page >= page.Meta.PageTotal) {
+		it.done = true
+	}
+	it.page++
+	it.buffer = page.Items
+	return nil
+}
@@ -0,0 +1,90 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	ID int `json:"id"`
+}
+
+// newPagedServer 按 page 参数返回 pages 中对应的页，pageTotal 为 meta.page_total（0 表示不返回 meta）
+// failPage 大于 0 时该页返回 API 错误
+func newPagedServer(t *testing.T, pages [][]testItem, pageTotal, failPage int) (*Client, *[]int) {
+	t.Helper()
+	var requested []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		require.NoError(t, err)
+		requested = append(requested, page)
+
+		if page == failPage {
+			w.Write([]byte(`{"code":40000,"message":"参数错误","data":{}}`))
+			return
+		}
+		var items []testItem
+		if page-1 < len(pages) {
+			items = pages[page-1]
+		}
+		data := map[string]interface{}{"items": items}
+		if pageTotal > 0 {
+			data["meta"] = PaginationMeta{Page: page, PageTotal: pageTotal, PageSize: 2}
+		}
+		body, _ := json.Marshal(map[string]interface{}{"code": 0, "data": data})
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewClient("token", WithBaseURL(server.URL), WithLogger(NopLogger()), WithoutRateLimit(), WithRetry(0, 0))
+	return client, &requested
+}
+
+func TestPageIteratorEmptyPage(t *testing.T) {
+	client, requested := newPagedServer(t, nil, 0, 0)
+
+	items, err := newPageIterator[testItem](client, "test/list", nil, 2).All(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, items)
+	assert.Equal(t, []int{1}, *requested)
+}
+
+func TestPageIteratorStopsAtLastPage(t *testing.T) {
+	pages := [][]testItem{{{ID: 1}, {ID: 2}}, {{ID: 3}}}
+	client, requested := newPagedServer(t, pages, 2, 0)
+
+	items, err := newPageIterator[testItem](client, "test/list", nil, 2).All(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []testItem{{ID: 1}, {ID: 2}, {ID: 3}}, items)
+	assert.Equal(t, []int{1, 2}, *requested, "page_total 达到后不再请求下一页")
+}
+
+func TestPageIteratorStopsAtEmptyPageWithoutMeta(t *testing.T) {
+	pages := [][]testItem{{{ID: 1}, {ID: 2}}}
+	client, requested := newPagedServer(t, pages, 0, 0)
+
+	items, err := newPageIterator[testItem](client, "test/list", nil, 2).All(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []testItem{{ID: 1}, {ID: 2}}, items)
+	assert.Equal(t, []int{1, 2}, *requested)
+}
+
+func TestPageIteratorErrorMidIteration(t *testing.T) {
+	pages := [][]testItem{{{ID: 1}, {ID: 2}}, {{ID: 3}}, {{ID: 4}}}
+	client, _ := newPagedServer(t, pages, 3, 2)
+	it := newPageIterator[testItem](client, "test/list", nil, 2)
+
+	items, err := it.All(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "获取第2页失败")
+	var kookErr *KOOKError
+	assert.ErrorAs(t, err, &kookErr)
+	assert.Equal(t, []testItem{{ID: 1}, {ID: 2}}, items, "出错前已取得的项仍然返回")
+}
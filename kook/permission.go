@@ -0,0 +1,145 @@
+package kook
+
+import (
+	"strings"
+)
+
+// Permission 单个权限位
+type Permission int
+
+// 角色与频道权限常量，取值与 KOOK 官方文档的权限位一致，可通过按位或组合
+// 用于 UpdateRoleParams.Permissions、UpdateChannelRolePermission/UpdateChannelUserPermission 的 allow/deny，
+// 以及 HasPermission 等读取服务端权限值的辅助函数
+const (
+	PermAdministrator       = 1 << 0  // 管理员
+	PermManageGuild         = 1 << 1  // 管理服务器
+	PermViewAuditLog        = 1 << 2  // 查看管理日志
+	PermCreateInvites       = 1 << 3  // 创建服务器邀请
+	PermManageInvites       = 1 << 4  // 管理邀请
+	PermManageChannels      = 1 << 5  // 频道管理
+	PermKickMembers         = 1 << 6  // 踢出用户
+	PermBanMembers          = 1 << 7  // 封禁用户
+	PermManageEmojis        = 1 << 8  // 管理自定义表情
+	PermChangeNickname      = 1 << 9  // 修改服务器昵称
+	PermManageRoles         = 1 << 10 // 管理角色权限
+	PermViewChannels        = 1 << 11 // 查看文字、语音频道
+	PermSendMessages        = 1 << 12 // 发布消息
+	PermManageMessages      = 1 << 13 // 管理消息
+	PermUploadFiles         = 1 << 14 // 上传文件
+	PermConnectVoice        = 1 << 15 // 语音连接
+	PermManageVoice         = 1 << 16 // 语音管理
+	PermMentionEveryone     = 1 << 17 // 提及@全体成员
+	PermAddReactions        = 1 << 18 // 添加反应
+	PermFollowReactions     = 1 << 19 // 跟随添加反应
+	PermPassiveConnectVoice = 1 << 20 // 被动连接语音频道
+	PermPushToTalkOnly      = 1 << 21 // 仅使用按键说话
+	PermUseVoiceActivity    = 1 << 22 // 使用自由麦
+	PermSpeak               = 1 << 23 // 说话
+	PermDeafenMembers       = 1 << 24 // 服务器静音
+	PermMuteMembers         = 1 << 25 // 服务器闭麦
+	PermManageNicknames     = 1 << 26 // 修改他人昵称
+	PermPlayMusic           = 1 << 27 // 播放伴奏
+	permMaxBit              = 27
+	PermAll                 = 1<<(permMaxBit+1) - 1 // 全部权限
+)
+
+// SDK 早期版本定义的权限常量，位值与 KOOK 官方权限位不一致，发送给服务端会授予错误的权限
+//
+// Deprecated: 使用取值与官方一致的 Perm* 常量，如 PermissionSendMessages 对应 PermSendMessages
+const (
+	PermissionViewChannel      = 1 << 0    // 查看频道
+	PermissionSendMessages     = 1 << 1    // 发送消息
+	PermissionManageMessages   = 1 << 2    // 管理消息
+	PermissionManageChannels   = 1 << 3    // 管理频道
+	PermissionConnectVoice     = 1 << 4    // 连接语音频道
+	PermissionSpeakVoice       = 1 << 5    // 语音频道中说话
+	PermissionMuteMembers      = 1 << 6    // 禁言成员
+	PermissionDeafenMembers    = 1 << 7    // 阻止成员听见
+	PermissionMoveMembers      = 1 << 8    // 移动成员
+	PermissionUseVoiceActivity = 1 << 9    // 使用按键说话
+	PermissionManageRoles      = 1 << 10   // 管理角色
+	PermissionManageGuild      = 1 << 11   // 管理服务器
+	PermissionCreateInvite     = 1 << 12   // 创建邀请
+	PermissionManageInvites    = 1 << 13   // 管理邀请
+	PermissionManageEmojis     = 1 << 14   // 管理表情
+	PermissionKickMembers      = 1 << 15   // 踢出成员
+	PermissionBanMembers       = 1 << 16   // 封禁成员
+	PermissionMentionEveryone  = 1 << 17   // 提及所有人
+	PermissionAddReactions     = 1 << 18   // 添加回应
+	PermissionUploadFiles      = 1 << 19   // 上传文件
+	PermissionUseSlashCommands = 1 << 20   // 使用斜杠命令
+	PermissionPlayMusic        = 1 << 21   // 播放音乐
+	PermissionAdministrator    = 1 << 22   // 管理员
+	PermissionAll              = 1<<23 - 1 // 全部权限
+)
+
+// permissionNames 权限名称，按官方权限位
+var permissionNames = map[Permission]string{
+	PermAdministrator:       "管理员",
+	PermManageGuild:         "管理服务器",
+	PermViewAuditLog:        "查看管理日志",
+	PermCreateInvites:       "创建服务器邀请",
+	PermManageInvites:       "管理邀请",
+	PermManageChannels:      "频道管理",
+	PermKickMembers:         "踢出用户",
+	PermBanMembers:          "封禁用户",
+	PermManageEmojis:        "管理自定义表情",
+	PermChangeNickname:      "修改服务器昵称",
+	PermManageRoles:         "管理角色权限",
+	PermViewChannels:        "查看文字、语音频道",
+	PermSendMessages:        "发布消息",
+	PermManageMessages:      "管理消息",
+	PermUploadFiles:         "上传文件",
+	PermConnectVoice:        "语音连接",
+	PermManageVoice:         "语音管理",
+	PermMentionEveryone:     "提及@全体成员",
+	PermAddReactions:        "添加反应",
+	PermFollowReactions:     "跟随添加反应",
+	PermPassiveConnectVoice: "被动连接语音频道",
+	PermPushToTalkOnly:      "仅使用按键说话",
+	PermUseVoiceActivity:    "使用自由麦",
+	PermSpeak:               "说话",
+	PermDeafenMembers:       "服务器静音",
+	PermMuteMembers:         "服务器闭麦",
+	PermManageNicknames:     "修改他人昵称",
+	PermPlayMusic:           "播放伴奏",
+}
+
+// String 返回权限名称
+func (p Permission) String() string {
+	if name, ok := permissionNames[p]; ok {
+		return name
+	}
+	return "未知权限"
+}
+
+// ParsePermissions 把权限值拆分为单个权限位，按位从低到高排列，未知位会被忽略
+func ParsePermissions(value int) []Permission {
+	var perms []Permission
+	for bit := 0; bit <= permMaxBit; bit++ {
+		p := Permission(1 << bit)
+		if value&int(p) != 0 {
+			perms = append(perms, p)
+		}
+	}
+	return perms
+}
+
+// HasPermission 判断权限值是否包含指定权限，管理员拥有全部权限
+// value 为服务端返回的权限值，perm 使用 Perm* 常量
+func HasPermission(value, perm int) bool {
+	if value&PermAdministrator != 0 {
+		return true
+	}
+	return value&perm == perm
+}
+
+// FormatPermissions 返回权限值的可读描述，如 "发布消息|添加反应"
+func FormatPermissions(value int) string {
+	perms := ParsePermissions(value)
+	names := make([]string, len(perms))
+	for i, p := range perms {
+		names[i] = p.String()
+	}
+	return strings.Join(names, "|")
+}
@@ -0,0 +1,60 @@
+package kook
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestPermissionValuesMatchOfficialBits 常量取值与 KOOK 官方文档一致
+func TestPermissionValuesMatchOfficialBits(t *testing.T) {
+	assert.Equal(t, 1, PermAdministrator)
+	assert.Equal(t, 2048, PermViewChannels)
+	assert.Equal(t, 4096, PermSendMessages)
+	assert.Equal(t, 262144, PermAddReactions)
+	assert.Equal(t, 134217728, PermPlayMusic)
+	assert.Equal(t, 1<<28-1, PermAll)
+}
+
+func TestHasPermission(t *testing.T) {
+	tests := []struct {
+		name  string
+		value int
+		perm  int
+		want  bool
+	}{
+		{"管理员拥有全部权限", PermAdministrator, PermManageGuild | PermBanMembers, true},
+		{"包含全部所需权限", PermViewChannels | PermSendMessages, PermSendMessages | PermViewChannels, true},
+		{"缺少部分权限", PermViewChannels, PermSendMessages | PermViewChannels, false},
+		{"第 22 位是使用自由麦而不是管理员", PermUseVoiceActivity, PermManageGuild, false},
+		{"没有任何权限", 0, PermSendMessages, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HasPermission(tt.value, tt.perm))
+		})
+	}
+}
+
+func TestParseAndFormatPermissions(t *testing.T) {
+	value := PermSendMessages | PermAddReactions | 1<<30
+	assert.Equal(t, []Permission{PermSendMessages, PermAddReactions}, ParsePermissions(value), "未知位被忽略")
+	assert.Equal(t, "发布消息|添加反应", FormatPermissions(value))
+	assert.Empty(t, FormatPermissions(0))
+}
+
+// TestGuildRoleHasPermission 按官方权限位解读服务端返回的角色权限值
+func TestGuildRoleHasPermission(t *testing.T) {
+	var role GuildRole
+	// 服务端返回的普通成员角色：查看频道、发布消息、语音连接、添加反应、使用自由麦、说话
+	assert.NoError(t, json.Unmarshal([]byte(`{"role_id":1,"name":"成员","permissions":12883968}`), &role))
+
+	assert.True(t, role.HasPermission(PermSendMessages|PermViewChannels))
+	assert.True(t, role.HasPermission(PermUseVoiceActivity))
+	assert.False(t, role.HasPermission(PermManageGuild), "使用自由麦（第 22 位）不代表管理员")
+	assert.False(t, role.HasPermission(PermAdministrator))
+
+	role.Permissions |= PermAdministrator
+	assert.True(t, role.HasPermission(PermManageGuild))
+}
@@ -0,0 +1,200 @@
+package kook
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// PollOption 投票选项
+type PollOption func(*Poll)
+
+// WithPollRouter 指定接收回应事件的路由器，默认使用 client.WebSocket() 返回的网关连接
+// 通过 Webhook 接收事件时传入对应的 WebhookHandler.EventRouter
+func WithPollRouter(router *EventRouter) PollOption {
+	return func(p *Poll) {
+		p.router = router
+	}
+}
+
+// WithPollDirectMessage 投票消息是私聊消息，添加回应与统计已有回应时使用私聊接口
+func WithPollDirectMessage() PollOption {
+	return func(p *Poll) {
+		p.direct = true
+	}
+}
+
+// Poll 把消息上的表情回应聚合为投票结果
+// 每个选项对应一个表情（unicode emoji 或服务器表情ID），同一用户对同一表情重复回应只计一票，
+// 取消回应时撤回该票；机器人自身添加的初始回应不计入结果
+type Poll struct {
+	client  *Client
+	router  *EventRouter
+	direct  bool
+	msgID   string
+	options []string
+	selfID  string
+
+	mu       sync.Mutex
+	votes    map[string]map[string]struct{} // 选项 -> 投票用户
+	onChange []func(results map[string]int)
+
+	unregister []func()
+	closeOnce  sync.Once
+}
+
+// NewPoll 为消息创建投票：开始监听回应事件，按顺序添加各选项的表情，并统计消息上已有的回应
+// 不再需要时调用 Close 停止监听
+func NewPoll(ctx context.Context, client *Client, msgID string, options []string, opts ...PollOption) (*Poll, error) {
+	if client == nil {
+		return nil, fmt.Errorf("客户端不能为空")
+	}
+	if msgID == "" {
+		return nil, fmt.Errorf("消息ID不能为空")
+	}
+	if len(options) == 0 {
+		return nil, fmt.Errorf("投票至少需要一个选项")
+	}
+
+	p := &Poll{
+		client:  client,
+		msgID:   msgID,
+		options: append([]string(nil), options...),
+		votes:   make(map[string]map[string]struct{}, len(options)),
+	}
+	for _, option := range options {
+		if option == "" {
+			return nil, fmt.Errorf("投票选项不能为空")
+		}
+		if _, ok := p.votes[option]; ok {
+			return nil, fmt.Errorf("投票选项重复: %s", option)
+		}
+		p.votes[option] = make(map[string]struct{})
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.router == nil {
+		p.router = client.WebSocket().EventRouter
+	}
+
+	selfID, err := client.User.SelfID(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("获取机器人自身ID失败: %w", err)
+	}
+	p.selfID = selfID
+
+	// 先监听再添加回应，避免错过添加期间的投票
+	p.unregister = append(p.unregister,
+		p.router.OnReactionAdded(func(_ context.Context, e *ReactionEvent) { p.apply(e, true) }),
+		p.router.OnReactionRemoved(func(_ context.Context, e *ReactionEvent) { p.apply(e, false) }),
+	)
+
+	addReactions, listUsers := client.Message.AddReactions, client.Message.GetAllReactionUsers
+	if p.direct {
+		addReactions, listUsers = client.Message.AddDirectReactions, client.Message.GetAllDirectReactionUsers
+	}
+	if err := addReactions(ctx, msgID, p.options); err != nil {
+		p.Close()
+		return nil, err
+	}
+
+	// 统计创建投票之前已有的回应
+	for _, option := range p.options {
+		users, err := listUsers(ctx, msgID, option)
+		if err != nil {
+			p.Close()
+			return nil, fmt.Errorf("获取选项 %s 的回应用户失败: %w", option, err)
+		}
+		for _, u := range users {
+			p.vote(option, u.ID, true)
+		}
+	}
+	return p, nil
+}
+
+// MsgID 返回投票所在的消息ID
+func (p *Poll) MsgID() string {
+	return p.msgID
+}
+
+// Results 返回各选项当前的票数快照
+func (p *Poll) Results() map[string]int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.resultsLocked()
+}
+
+// OnChange 注册票数变化回调，参数为变化后的票数快照；回调在事件处理协程中执行
+func (p *Poll) OnChange(handler func(results map[string]int)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.onChange = append(p.onChange, handler)
+}
+
+// Close 停止监听回应事件，之后票数不再变化（可重复调用）
+func (p *Poll) Close() {
+	p.closeOnce.Do(func() {
+		for _, unregister := range p.unregister {
+			unregister()
+		}
+	})
+}
+
+// apply 处理一条回应事件，只统计本消息上、属于投票选项的其他用户回应
+func (p *Poll) apply(e *ReactionEvent, added bool) {
+	if e.MsgID != p.msgID {
+		return
+	}
+	option, ok := p.optionFor(e.Emoji)
+	if !ok {
+		return
+	}
+	p.vote(option, e.UserID, added)
+}
+
+// optionFor 按表情ID或名称匹配投票选项
+func (p *Poll) optionFor(emoji Emoji) (string, bool) {
+	for _, option := range p.options {
+		if option == emoji.ID || option == emoji.Name {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// vote 记录或撤回一票，票数确实变化时通知回调
+func (p *Poll) vote(option, userID string, added bool) {
+	if userID == "" || userID == p.selfID {
+		return
+	}
+
+	p.mu.Lock()
+	voters := p.votes[option]
+	_, voted := voters[userID]
+	if voted == added {
+		// 重复回应或取消不存在的回应，票数不变
+		p.mu.Unlock()
+		return
+	}
+	if added {
+		voters[userID] = struct{}{}
+	} else {
+		delete(voters, userID)
+	}
+	handlers := p.onChange
+	results := p.resultsLocked()
+	p.mu.Unlock()
+
+	for _, h := range handlers {
+		h(results)
+	}
+}
+
+func (p *Poll) resultsLocked() map[string]int {
+	results := make(map[string]int, len(p.votes))
+	for option, voters := range p.votes {
+		results[option] = len(voters)
+	}
+	return results
+}
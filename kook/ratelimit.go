@@ -1,14 +1,18 @@
 package kook
 
 import (
+	"context"
+	"net/http"
+	"strconv"
 	"sync"
 	"time"
 )
 
-// RateLimiter 速率限制器
+// RateLimiter 速率限制器（令牌桶）
+// 令牌在取用时按距上次补充的时间计算，不需要后台协程，限制器不再使用时无需关闭
 type RateLimiter struct {
-	tokens     chan struct{}
-	refillMu   sync.Mutex
+	mu         sync.Mutex
+	tokens     int
 	lastRefill time.Time
 	rate       time.Duration
 	burst      int
@@ -18,59 +22,67 @@ type RateLimiter struct {
 // rate: 令牌补充间隔
 // burst: 令牌桶容量
 func NewRateLimiter(rate time.Duration, burst int) *RateLimiter {
-	rl := &RateLimiter{
-		tokens:     make(chan struct{}, burst),
+	// 初始填满令牌桶
+	return &RateLimiter{
+		tokens:     burst,
 		lastRefill: time.Now(),
 		rate:       rate,
 		burst:      burst,
 	}
-
-	// 初始填满令牌桶
-	for i := 0; i < burst; i++ {
-		select {
-		case rl.tokens <- struct{}{}:
-		default:
-		}
-	}
-
-	// 启动令牌补充协程
-	go rl.refillLoop()
-
-	return rl
 }
 
 // Wait 等待获取令牌
 func (rl *RateLimiter) Wait() {
-	<-rl.tokens
+	_ = rl.WaitContext(context.Background())
+}
+
+// WaitContext 等待获取令牌，ctx 结束时放弃等待并返回 ctx.Err()
+func (rl *RateLimiter) WaitContext(ctx context.Context) error {
+	for {
+		wait, ok := rl.take(time.Now())
+		if ok {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
 }
 
 // TryAcquire 尝试获取令牌，不等待
 func (rl *RateLimiter) TryAcquire() bool {
-	select {
-	case <-rl.tokens:
-		return true
-	default:
-		return false
-	}
+	_, ok := rl.take(time.Now())
+	return ok
 }
 
-// refillLoop 令牌补充循环
-func (rl *RateLimiter) refillLoop() {
-	ticker := time.NewTicker(rl.rate)
-	defer ticker.Stop()
+// take 补充令牌后尝试取出一个，取不到时返回距下一个令牌的等待时间
+func (rl *RateLimiter) take(now time.Time) (time.Duration, bool) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
 
-	for range ticker.C {
-		rl.refillMu.Lock()
-		// 尝试添加一个令牌
-		select {
-		case rl.tokens <- struct{}{}:
-			// 成功添加令牌
-		default:
-			// 令牌桶已满
+	if rl.rate > 0 {
+		if n := int(now.Sub(rl.lastRefill) / rl.rate); n > 0 {
+			rl.tokens += n
+			rl.lastRefill = rl.lastRefill.Add(time.Duration(n) * rl.rate)
 		}
-		rl.lastRefill = time.Now()
-		rl.refillMu.Unlock()
+	} else {
+		rl.tokens = rl.burst
+	}
+	if rl.tokens >= rl.burst {
+		// 令牌桶已满，从现在开始计时下一个令牌
+		rl.tokens = rl.burst
+		rl.lastRefill = now
+	}
+
+	if rl.tokens > 0 {
+		rl.tokens--
+		return 0, true
 	}
+	return rl.rate - now.Sub(rl.lastRefill), false
 }
 
 // EndpointRateLimiter 端点级别的速率限制器
@@ -95,6 +107,11 @@ func (erl *EndpointRateLimiter) Wait(endpoint string) {
 	erl.getLimiter(endpoint).Wait()
 }
 
+// WaitContext 等待指定端点的令牌，ctx 结束时放弃等待并返回 ctx.Err()
+func (erl *EndpointRateLimiter) WaitContext(ctx context.Context, endpoint string) error {
+	return erl.getLimiter(endpoint).WaitContext(ctx)
+}
+
 // TryAcquire 尝试获取指定端点的令牌
 func (erl *EndpointRateLimiter) TryAcquire(endpoint string) bool {
 	return erl.getLimiter(endpoint).TryAcquire()
@@ -142,10 +159,17 @@ func NewGlobalRateLimiter() *GlobalRateLimiter {
 
 // Wait 等待令牌（同时检查全局和端点限制）
 func (grl *GlobalRateLimiter) Wait(endpoint string) {
+	_ = grl.WaitContext(context.Background(), endpoint)
+}
+
+// WaitContext 等待令牌（同时检查全局和端点限制），ctx 结束时放弃等待并返回 ctx.Err()
+func (grl *GlobalRateLimiter) WaitContext(ctx context.Context, endpoint string) error {
 	// 先等待全局限制
-	grl.generalLimiter.Wait()
+	if err := grl.generalLimiter.WaitContext(ctx); err != nil {
+		return err
+	}
 	// 再等待端点限制
-	grl.endpointLimiter.Wait(endpoint)
+	return grl.endpointLimiter.WaitContext(ctx, endpoint)
 }
 
 // TryAcquire 尝试获取令牌
@@ -162,3 +186,157 @@ func (grl *GlobalRateLimiter) TryAcquire(endpoint string) bool {
 	return true
 }
 
+// RateLimitWaitFunc 因限流而等待时的回调
+type RateLimitWaitFunc func(bucket string, wait time.Duration)
+
+// BucketRateLimiter 基于 KOOK 响应头（X-Rate-Limit-*）的 bucket 级限流器
+// 每次响应后记录对应 bucket 的剩余配额与重置时间，请求前若配额耗尽则阻塞到重置
+type BucketRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*rateLimitBucket
+	endpoints map[string]string // 端点 -> bucket
+	global    rateLimitBucket
+	onWait    RateLimitWaitFunc
+}
+
+// rateLimitBucket 单个 bucket 的配额状态
+type rateLimitBucket struct {
+	limit     int
+	remaining int
+	resetAt   time.Time
+}
+
+// GlobalRateLimitBucket Status 中全局限流配额使用的 bucket 名
+const GlobalRateLimitBucket = "global"
+
+// BucketStatus bucket 配额快照
+type BucketStatus struct {
+	Remaining int       // 剩余配额，包含本地已占用但尚未收到响应的请求
+	Limit     int       // 重置周期内的总配额
+	ResetAt   time.Time // 配额重置时间
+}
+
+// NewBucketRateLimiter 创建基于响应头的限流器
+func NewBucketRateLimiter(onWait RateLimitWaitFunc) *BucketRateLimiter {
+	return &BucketRateLimiter{
+		buckets:   make(map[string]*rateLimitBucket),
+		endpoints: make(map[string]string),
+		onWait:    onWait,
+	}
+}
+
+// Wait 等待直到端点所在 bucket 及全局配额可用
+func (l *BucketRateLimiter) Wait(ctx context.Context, endpoint string) error {
+	for {
+		bucketName, wait := l.reserve(endpoint)
+		if wait <= 0 {
+			return nil
+		}
+
+		if l.onWait != nil {
+			l.onWait(bucketName, wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+// reserve 尝试占用一个配额，配额不足时返回需要等待的时长
+func (l *BucketRateLimiter) reserve(endpoint string) (string, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	// 全局限流优先
+	if l.global.remaining <= 0 && now.Before(l.global.resetAt) {
+		return "", l.global.resetAt.Sub(now)
+	}
+
+	bucketName, ok := l.endpoints[endpoint]
+	if !ok {
+		return bucketName, 0
+	}
+	bucket := l.buckets[bucketName]
+	if bucket == nil {
+		return bucketName, 0
+	}
+
+	if !now.Before(bucket.resetAt) {
+		// 已过重置时间，恢复满额等待下次响应头校准
+		bucket.remaining = bucket.limit
+	}
+	if bucket.remaining <= 0 {
+		return bucketName, bucket.resetAt.Sub(now)
+	}
+
+	bucket.remaining--
+	return bucketName, 0
+}
+
+// Update 根据响应头更新配额状态
+// bucket 为空或带有 X-Rate-Limit-Global 头时视为全局限流
+func (l *BucketRateLimiter) Update(endpoint string, header http.Header) {
+	remainingStr := header.Get("X-Rate-Limit-Remaining")
+	resetStr := header.Get("X-Rate-Limit-Reset")
+	if remainingStr == "" && resetStr == "" {
+		return
+	}
+
+	limit, _ := strconv.Atoi(header.Get("X-Rate-Limit-Limit"))
+	remaining, err := strconv.Atoi(remainingStr)
+	if err != nil {
+		remaining = 0
+	}
+	resetSeconds, _ := strconv.ParseFloat(resetStr, 64)
+	resetAt := time.Now().Add(time.Duration(resetSeconds * float64(time.Second)))
+
+	state := rateLimitBucket{
+		limit:     limit,
+		remaining: remaining,
+		resetAt:   resetAt,
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucketName := header.Get("X-Rate-Limit-Bucket")
+	if bucketName == "" || header.Get("X-Rate-Limit-Global") != "" {
+		l.global = state
+		return
+	}
+
+	l.endpoints[endpoint] = bucketName
+	l.buckets[bucketName] = &state
+}
+
+// Status 返回各 bucket 配额的只读快照，键为响应头中的 bucket 名，全局限流为 GlobalRateLimitBucket
+// 数据来自最近一次响应头，已过重置时间的 bucket 视为满额
+func (l *BucketRateLimiter) Status() map[string]BucketStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	snapshot := func(b rateLimitBucket) BucketStatus {
+		status := BucketStatus{Remaining: b.remaining, Limit: b.limit, ResetAt: b.resetAt}
+		if !now.Before(b.resetAt) {
+			status.Remaining = b.limit
+		}
+		return status
+	}
+
+	status := make(map[string]BucketStatus, len(l.buckets)+1)
+	for name, bucket := range l.buckets {
+		status[name] = snapshot(*bucket)
+	}
+	if !l.global.resetAt.IsZero() {
+		status[GlobalRateLimitBucket] = snapshot(l.global)
+	}
+	return status
+}
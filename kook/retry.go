@@ -2,11 +2,12 @@ package kook
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"net"
 	"net/http"
-	"net/url"
+	"strconv"
 	"syscall"
 	"time"
 )
@@ -39,35 +40,25 @@ func IsRetryableError(err error) bool {
 		return false
 	}
 
-	// 网络相关错误
-	if netErr, ok := err.(net.Error); ok {
-		return netErr.Timeout() || netErr.Temporary()
-	}
-
-	// URL 错误
-	if urlErr, ok := err.(*url.Error); ok {
-		return IsRetryableError(urlErr.Err)
+	// KOOK API 错误（APIError 为其别名）
+	var kookErr *KOOKError
+	if errors.As(err, &kookErr) {
+		return kookErr.IsRetryable()
 	}
 
 	// 系统调用错误
-	if opErr, ok := err.(*net.OpError); ok {
-		if syscallErr, ok := opErr.Err.(*syscall.Errno); ok {
-			switch *syscallErr {
-			case syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.ETIMEDOUT:
-				return true
-			}
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.ECONNRESET, syscall.ECONNREFUSED, syscall.ETIMEDOUT:
+			return true
 		}
 	}
 
-	// KOOK API 错误
-	if kookErr, ok := err.(*KOOKError); ok {
-		return kookErr.IsRetryable()
-	}
-
-	// 向后兼容的 APIError 检查
-	if apiErr, ok := err.(*APIError); ok {
-		// KOOK 5xx 是 50xxx；42900 为限流
-		return (apiErr.Code >= 50000 && apiErr.Code < 60000) || apiErr.Code == 429 || apiErr.Code == 42900
+	// 网络相关错误（包括被 url.Error 包装的情况）
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Timeout() || netErr.Temporary()
 	}
 
 	return false
@@ -75,12 +66,10 @@ func IsRetryableError(err error) bool {
 
 // IsRateLimitError 判断是否为速率限制错误
 func IsRateLimitError(err error) bool {
-	if kookErr, ok := err.(*KOOKError); ok {
+	var kookErr *KOOKError
+	if errors.As(err, &kookErr) {
 		return kookErr.IsRateLimited()
 	}
-	if apiErr, ok := err.(*APIError); ok {
-		return apiErr.Code == 429
-	}
 	return false
 }
 
@@ -112,17 +101,32 @@ func DoWithRetry(ctx context.Context, fn RetryableFunc, config *RetryConfig, log
 		if attempt > 0 {
 			delay := GetRetryDelay(attempt-1, config)
 
-			if IsRateLimitError(lastErr) {
+			var kookErr *KOOKError
+			if errors.As(lastErr, &kookErr) && kookErr.RetryAfter > 0 {
+				// 服务端明确给出了等待时间
+				delay = kookErr.RetryAfter
+			} else if IsRateLimitError(lastErr) {
 				// 速率限制错误，使用更长的延迟
 				delay = delay * 2
+			}
+
+			// 剩余时间不足以等待时直接放弃，避免睡过 ctx 的 deadline
+			if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+				logger.Warnf("剩余时间不足以等待 %v 后重试，放弃重试", delay)
+				break
+			}
+
+			if IsRateLimitError(lastErr) {
 				logger.Warnf("遇到速率限制错误，等待 %v 后重试 (第 %d 次)", delay, attempt)
 			} else {
 				logger.Warnf("请求失败，等待 %v 后重试 (第 %d 次): %v", delay, attempt, lastErr)
 			}
 
+			timer := time.NewTimer(delay)
 			select {
-			case <-time.After(delay):
+			case <-timer.C:
 			case <-ctx.Done():
+				timer.Stop()
 				return nil, ctx.Err()
 			}
 		}
@@ -151,14 +155,6 @@ func DoWithRetry(ctx context.Context, fn RetryableFunc, config *RetryConfig, log
 	return nil, fmt.Errorf("重试失败: %w", lastErr)
 }
 
-// Logger 日志接口
-type Logger interface {
-	Debugf(format string, args ...interface{})
-	Infof(format string, args ...interface{})
-	Warnf(format string, args ...interface{})
-	Errorf(format string, args ...interface{})
-}
-
 // ExtractRetryAfter 从 HTTP 响应头中提取 Retry-After 值
 func ExtractRetryAfter(resp *http.Response) time.Duration {
 	if resp == nil {
@@ -185,3 +181,16 @@ func ExtractRetryAfter(resp *http.Response) time.Duration {
 
 	return 0
 }
+
+// rateLimitResetDelay 从 X-Rate-Limit-Reset 头中提取配额重置的剩余时间
+func rateLimitResetDelay(header http.Header) time.Duration {
+	reset := header.Get("X-Rate-Limit-Reset")
+	if reset == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseFloat(reset, 64)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -1,6 +1,7 @@
 package kook
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -42,6 +43,19 @@ func (s *RoleService) GetRoleList(ctx context.Context, guildID string, page, pag
 	return &result, nil
 }
 
+// IterateRoles 创建服务器角色列表迭代器
+func (s *RoleService) IterateRoles(ctx context.Context, guildID string) *PageIterator[GuildRole] {
+	if guildID == "" {
+		return newPageIteratorError[GuildRole](fmt.Errorf("服务器ID不能为空"))
+	}
+	return newPageIterator[GuildRole](s.client, "guild-role/list", map[string]string{"guild_id": guildID}, 50)
+}
+
+// ListRoles 获取服务器的全部角色（自动翻页）
+func (s *RoleService) ListRoles(ctx context.Context, guildID string) ([]GuildRole, error) {
+	return s.IterateRoles(ctx, guildID).All(ctx)
+}
+
 // CreateRole 创建服务器角色
 func (s *RoleService) CreateRole(ctx context.Context, guildID string, name string) (*GuildRole, error) {
 	if guildID == "" {
@@ -61,16 +75,7 @@ func (s *RoleService) CreateRole(ctx context.Context, guildID string, name strin
 		return nil, err
 	}
 
-	var roles []GuildRole
-	if err := json.Unmarshal(resp.Data, &roles); err != nil {
-		return nil, fmt.Errorf("解析角色信息失败: %w", err)
-	}
-
-	if len(roles) == 0 {
-		return nil, fmt.Errorf("未返回角色信息")
-	}
-
-	return &roles[0], nil
+	return decodeRole(resp.Data)
 }
 
 // UpdateRole 更新服务器角色
@@ -108,16 +113,7 @@ func (s *RoleService) UpdateRole(ctx context.Context, guildID string, roleID int
 		return nil, err
 	}
 
-	var roles []GuildRole
-	if err := json.Unmarshal(resp.Data, &roles); err != nil {
-		return nil, fmt.Errorf("解析角色信息失败: %w", err)
-	}
-
-	if len(roles) == 0 {
-		return nil, fmt.Errorf("未返回角色信息")
-	}
-
-	return &roles[0], nil
+	return decodeRole(resp.Data)
 }
 
 // DeleteRole 删除服务器角色
@@ -200,6 +196,27 @@ func (s *RoleService) RevokeRole(ctx context.Context, guildID, userID string, ro
 	return &result, nil
 }
 
+// decodeRole 解析角色信息，接口返回单个对象，兼容旧版本返回的数组形式
+func decodeRole(data json.RawMessage) (*GuildRole, error) {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var roles []GuildRole
+		if err := json.Unmarshal(trimmed, &roles); err != nil {
+			return nil, fmt.Errorf("解析角色信息失败: %w", err)
+		}
+		if len(roles) == 0 {
+			return nil, fmt.Errorf("未返回角色信息")
+		}
+		return &roles[0], nil
+	}
+
+	var role GuildRole
+	if err := json.Unmarshal(trimmed, &role); err != nil {
+		return nil, fmt.Errorf("解析角色信息失败: %w", err)
+	}
+	return &role, nil
+}
+
 // 数据结构定义
 
 // GuildRole 服务器角色信息
@@ -213,6 +230,21 @@ type GuildRole struct {
 	Permissions int `json:"permissions"`  // 权限值
 }
 
+// IsHoisted 是否在用户列表中单独显示
+func (r *GuildRole) IsHoisted() bool {
+	return r.Hoist == 1
+}
+
+// IsMentionable 是否可以被提及
+func (r *GuildRole) IsMentionable() bool {
+	return r.Mentionable == 1
+}
+
+// HasPermission 判断角色是否拥有指定权限，perm 使用 Perm* 常量；拥有 PermAdministrator 的角色拥有全部权限
+func (r *GuildRole) HasPermission(perm int) bool {
+	return HasPermission(r.Permissions, perm)
+}
+
 // UpdateRoleParams 更新角色参数
 type UpdateRoleParams struct {
 	Name        string `json:"name,omitempty"`        // 角色名称
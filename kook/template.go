@@ -0,0 +1,230 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// TemplateService 消息模板相关API服务（template/*）
+// 创建模板返回的 ID 填入 SendMessageParams.TemplateID，配合 WithTemplateData 发送模板消息
+type TemplateService struct {
+	client *Client
+}
+
+// 模板语法类型
+const (
+	TemplateTypeTwig = 0 // Twig 模板
+)
+
+// 模板渲染后的消息类型
+const (
+	TemplateMsgTypeText      = 1 // 文字
+	TemplateMsgTypeKMarkdown = 2 // KMarkdown
+	TemplateMsgTypeCard      = 3 // 卡片消息
+)
+
+// 模板审核状态
+const (
+	TemplateStatusPending  = 0 // 审核中
+	TemplateStatusApproved = 1 // 审核通过
+	TemplateStatusRejected = 2 // 审核拒绝
+)
+
+// Template 消息模板
+type Template struct {
+	ID          string `json:"id"`           // 模板ID，用于 SendMessageParams.TemplateID
+	Title       string `json:"title"`        // 模板标题
+	Type        int    `json:"type"`         // 模板语法类型，见 TemplateTypeTwig
+	MsgType     int    `json:"msgtype"`      // 渲染后的消息类型，见 TemplateMsgType*
+	Status      int    `json:"status"`       // 审核状态，见 TemplateStatus*
+	Content     string `json:"content"`      // 模板内容
+	TestData    string `json:"test_data"`    // 测试数据（JSON）
+	TestChannel string `json:"test_channel"` // 测试频道ID
+}
+
+// UnmarshalJSON 兼容模板ID以数字或字符串返回的情况
+func (t *Template) UnmarshalJSON(data []byte) error {
+	type plain Template
+	var raw struct {
+		*plain
+		ID json.RawMessage `json:"id"`
+	}
+	raw.plain = (*plain)(t)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	t.ID = ""
+	if len(raw.ID) == 0 || string(raw.ID) == "null" {
+		return nil
+	}
+	var id json.Number
+	if err := json.Unmarshal(raw.ID, &id); err == nil {
+		t.ID = id.String()
+		return nil
+	}
+	if err := json.Unmarshal(raw.ID, &t.ID); err != nil {
+		return fmt.Errorf("解析模板ID失败: %w", err)
+	}
+	return nil
+}
+
+// CreateTemplateParams 创建模板参数
+type CreateTemplateParams struct {
+	Title       string      // 模板标题
+	Type        int         // 模板语法类型，默认 TemplateTypeTwig
+	MsgType     int         // 渲染后的消息类型，默认 TemplateMsgTypeKMarkdown
+	Content     string      // 模板内容
+	TestData    interface{} // 测试数据，字符串原样发送，其他值序列化为 JSON
+	TestChannel string      // 测试频道ID
+}
+
+// UpdateTemplateParams 更新模板参数，空字符串与 nil 字段不修改
+// Type、MsgType 使用指针区分“不修改”与取值 0
+type UpdateTemplateParams struct {
+	Title       string      // 模板标题
+	Type        *int        // 模板语法类型
+	MsgType     *int        // 渲染后的消息类型，见 TemplateMsgType*
+	Content     string      // 模板内容
+	TestData    interface{} // 测试数据，字符串原样发送，其他值序列化为 JSON
+	TestChannel string      // 测试频道ID
+}
+
+// IterateTemplates 创建消息模板列表迭代器
+func (s *TemplateService) IterateTemplates(ctx context.Context) *PageIterator[Template] {
+	return newPageIterator[Template](s.client, "template/list", nil, 50)
+}
+
+// ListTemplates 获取全部消息模板（自动翻页）
+func (s *TemplateService) ListTemplates(ctx context.Context) ([]Template, error) {
+	return s.IterateTemplates(ctx).All(ctx)
+}
+
+// CreateTemplate 创建消息模板，返回的模板ID可用于 SendMessageParams.TemplateID
+// 新建的模板需要审核通过（Status 为 TemplateStatusApproved）后才能用于发送
+func (s *TemplateService) CreateTemplate(ctx context.Context, params CreateTemplateParams) (*Template, error) {
+	if params.Title == "" {
+		return nil, fmt.Errorf("模板标题不能为空")
+	}
+	if params.Content == "" {
+		return nil, fmt.Errorf("模板内容不能为空")
+	}
+
+	msgType := params.MsgType
+	if msgType <= 0 {
+		msgType = TemplateMsgTypeKMarkdown
+	}
+	if err := validateTemplateMsgType(msgType); err != nil {
+		return nil, err
+	}
+
+	requestParams := map[string]interface{}{
+		"title":   params.Title,
+		"type":    params.Type,
+		"msgtype": msgType,
+		"content": params.Content,
+	}
+	if err := setTemplateTestData(requestParams, params.TestData); err != nil {
+		return nil, err
+	}
+	if params.TestChannel != "" {
+		requestParams["test_channel"] = params.TestChannel
+	}
+
+	return s.postTemplate(ctx, "template/create", requestParams)
+}
+
+// UpdateTemplate 更新消息模板，修改内容后需要重新审核
+func (s *TemplateService) UpdateTemplate(ctx context.Context, id string, params UpdateTemplateParams) (*Template, error) {
+	if id == "" {
+		return nil, fmt.Errorf("模板ID不能为空")
+	}
+
+	requestParams := map[string]interface{}{
+		"id": id,
+	}
+	if params.Title != "" {
+		requestParams["title"] = params.Title
+	}
+	if params.Type != nil {
+		requestParams["type"] = *params.Type
+	}
+	if params.MsgType != nil {
+		if err := validateTemplateMsgType(*params.MsgType); err != nil {
+			return nil, err
+		}
+		requestParams["msgtype"] = *params.MsgType
+	}
+	if params.Content != "" {
+		requestParams["content"] = params.Content
+	}
+	if err := setTemplateTestData(requestParams, params.TestData); err != nil {
+		return nil, err
+	}
+	if params.TestChannel != "" {
+		requestParams["test_channel"] = params.TestChannel
+	}
+
+	return s.postTemplate(ctx, "template/update", requestParams)
+}
+
+// DeleteTemplate 删除消息模板
+func (s *TemplateService) DeleteTemplate(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("模板ID不能为空")
+	}
+
+	_, err := s.client.Post(ctx, "template/delete", map[string]interface{}{"id": id})
+	return err
+}
+
+// postTemplate 创建或更新模板，兼容直接返回模板与包在 model 字段中返回两种格式
+func (s *TemplateService) postTemplate(ctx context.Context, endpoint string, params map[string]interface{}) (*Template, error) {
+	resp, err := s.client.Post(ctx, endpoint, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapped struct {
+		Model *Template `json:"model"`
+	}
+	if err := json.Unmarshal(resp.Data, &wrapped); err == nil && wrapped.Model != nil {
+		return wrapped.Model, nil
+	}
+
+	var template Template
+	if err := json.Unmarshal(resp.Data, &template); err != nil {
+		return nil, fmt.Errorf("解析模板信息失败: %w", err)
+	}
+	return &template, nil
+}
+
+// validateTemplateMsgType 校验模板渲染后的消息类型
+func validateTemplateMsgType(msgType int) error {
+	switch msgType {
+	case TemplateMsgTypeText, TemplateMsgTypeKMarkdown, TemplateMsgTypeCard:
+		return nil
+	default:
+		return NewValidationErrorWithValue("msgtype", "模板消息类型只能是文字、KMarkdown 或卡片", strconv.Itoa(msgType))
+	}
+}
+
+// setTemplateTestData 把测试数据写入请求参数，非字符串的值序列化为 JSON
+func setTemplateTestData(params map[string]interface{}, data interface{}) error {
+	switch v := data.(type) {
+	case nil:
+	case string:
+		if v != "" {
+			params["test_data"] = v
+		}
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("序列化模板测试数据失败: %w", err)
+		}
+		params["test_data"] = string(encoded)
+	}
+	return nil
+}
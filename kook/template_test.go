@@ -0,0 +1,62 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUpdateTemplateOptionalFields Type 与 MsgType 为 nil 时不修改，设置时 MsgType 需要合法
+func TestUpdateTemplateOptionalFields(t *testing.T) {
+	bodies := make(chan map[string]interface{}, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		bodies <- body
+		w.Write([]byte(`{"code":0,"message":"","data":{"id":1}}`))
+	}))
+	defer server.Close()
+	client := NewClient("token", WithBaseURL(server.URL+"/api"), WithLogger(NopLogger()), WithoutRateLimit())
+
+	_, err := client.Template.UpdateTemplate(context.Background(), "1", UpdateTemplateParams{Title: "title"})
+	require.NoError(t, err)
+	body := <-bodies
+	assert.NotContains(t, body, "type")
+	assert.NotContains(t, body, "msgtype")
+
+	twig, card := TemplateTypeTwig, TemplateMsgTypeCard
+	_, err = client.Template.UpdateTemplate(context.Background(), "1", UpdateTemplateParams{Type: &twig, MsgType: &card})
+	require.NoError(t, err)
+	body = <-bodies
+	assert.Equal(t, float64(TemplateTypeTwig), body["type"])
+	assert.Equal(t, float64(TemplateMsgTypeCard), body["msgtype"])
+
+	invalid := 0
+	_, err = client.Template.UpdateTemplate(context.Background(), "1", UpdateTemplateParams{MsgType: &invalid})
+	var validationErr *ValidationError
+	assert.ErrorAs(t, err, &validationErr)
+	assert.Empty(t, bodies, "非法的消息类型不发送请求")
+}
+
+// TestSendMessageTemplateDataRequiresTemplateID 只设置模板参数而没有模板ID时报错，不会把参数 JSON 当作普通消息发出
+func TestSendMessageTemplateDataRequiresTemplateID(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":0,"message":"","data":{"msg_id":"1"}}`))
+	}))
+	defer server.Close()
+	client := NewClient("token", WithBaseURL(server.URL+"/api"), WithLogger(NopLogger()), WithoutRateLimit())
+
+	params := SendMessageParams{TargetID: "channel"}.WithTemplateData(map[string]string{"name": "kook"})
+	_, err := client.Message.SendMessage(context.Background(), params)
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "template_id", validationErr.Field)
+	assert.Zero(t, requests)
+}
@@ -0,0 +1,264 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// 系统事件（type 255）的 extra.type 子类型
+const (
+	SystemEventAddedReaction          = "added_reaction"
+	SystemEventDeletedReaction        = "deleted_reaction"
+	SystemEventUpdatedMessage         = "updated_message"
+	SystemEventDeletedMessage         = "deleted_message"
+	SystemEventPinnedMessage          = "pinned_message"
+	SystemEventUnpinnedMessage        = "unpinned_message"
+	SystemEventAddedChannel           = "added_channel"
+	SystemEventUpdatedChannel         = "updated_channel"
+	SystemEventDeletedChannel         = "deleted_channel"
+	SystemEventPrivateAddedReaction   = "private_added_reaction"
+	SystemEventPrivateDeletedReaction = "private_deleted_reaction"
+	SystemEventUpdatedPrivateMessage  = "updated_private_message"
+	SystemEventDeletedPrivateMessage  = "deleted_private_message"
+	SystemEventJoinedGuild            = "joined_guild"
+	SystemEventExitedGuild            = "exited_guild"
+	SystemEventUpdatedGuildMember     = "updated_guild_member"
+	SystemEventGuildMemberOnline      = "guild_member_online"
+	SystemEventGuildMemberOffline     = "guild_member_offline"
+	SystemEventAddedRole              = "added_role"
+	SystemEventDeletedRole            = "deleted_role"
+	SystemEventUpdatedRole            = "updated_role"
+	SystemEventUpdatedGuild           = "updated_guild"
+	SystemEventDeletedGuild           = "deleted_guild"
+	SystemEventJoinedChannel          = "joined_channel"
+	SystemEventExitedChannel          = "exited_channel"
+	SystemEventUserUpdated            = "user_updated"
+	SystemEventSelfJoinedGuild        = "self_joined_guild"
+	SystemEventSelfExitedGuild        = "self_exited_guild"
+	SystemEventMessageButtonClick     = "message_btn_click"
+)
+
+// TextMessageEvent 文字/KMarkdown消息事件
+type TextMessageEvent struct {
+	*Event
+	GuildID      string
+	ChannelName  string
+	Author       User
+	Mention      []string
+	MentionRoles []int
+	MentionAll   bool
+	MentionHere  bool
+}
+
+// SystemEvent 系统事件（type 255），Body 为子类型对应的原始数据
+type SystemEvent struct {
+	*Event
+	Type string
+	Body json.RawMessage
+}
+
+// ReactionEvent 添加/取消回应事件（频道与私聊）
+type ReactionEvent struct {
+	*Event    `json:"-"`
+	MsgID     string `json:"msg_id"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	ChatCode  string `json:"chat_code"`
+	Emoji     Emoji  `json:"emoji"`
+}
+
+// UserChannelEvent 用户加入/退出语音频道事件
+type UserChannelEvent struct {
+	*Event    `json:"-"`
+	UserID    string `json:"user_id"`
+	ChannelID string `json:"channel_id"`
+	JoinedAt  int64  `json:"joined_at"`
+	ExitedAt  int64  `json:"exited_at"`
+}
+
+// UserGuildEvent 用户加入/退出服务器事件
+type UserGuildEvent struct {
+	*Event   `json:"-"`
+	UserID   string `json:"user_id"`
+	JoinedAt int64  `json:"joined_at"`
+	ExitedAt int64  `json:"exited_at"`
+}
+
+// MessageUpdatedEvent 消息更新事件
+type MessageUpdatedEvent struct {
+	*Event       `json:"-"`
+	MsgID        string   `json:"msg_id"`
+	ChannelID    string   `json:"channel_id"`
+	ChatCode     string   `json:"chat_code"`
+	Content      string   `json:"content"`
+	Mention      []string `json:"mention"`
+	MentionAll   bool     `json:"mention_all"`
+	MentionHere  bool     `json:"mention_here"`
+	MentionRoles []int    `json:"mention_roles"`
+	UpdatedAt    int64    `json:"updated_at"`
+}
+
+// MessageDeletedEvent 消息删除事件
+type MessageDeletedEvent struct {
+	*Event    `json:"-"`
+	MsgID     string `json:"msg_id"`
+	ChannelID string `json:"channel_id"`
+	ChatCode  string `json:"chat_code"`
+}
+
+// ButtonClickEvent 卡片按钮点击事件
+type ButtonClickEvent struct {
+	*Event   `json:"-"`
+	MsgID    string `json:"msg_id"`
+	UserID   string `json:"user_id"`
+	Value    string `json:"value"`
+	TargetID string `json:"target_id"`
+	UserInfo User   `json:"user_info"`
+}
+
+// registerFunc 按事件类型注册处理器并返回注销函数
+type registerFunc func(eventType int, handler EventHandlerCtx) func()
+
+// onTextMessage 注册文字与 KMarkdown 消息处理器
+func onTextMessage(register registerFunc, logger Logger, handler func(context.Context, *TextMessageEvent)) func() {
+	adapter := func(ctx context.Context, event *Event) {
+		extra, err := event.ParseExtra()
+		if err != nil {
+			logger.Errorf("解析消息事件失败: %v", err)
+			return
+		}
+		handler(ctx, &TextMessageEvent{
+			Event:        event,
+			GuildID:      extra.GuildID,
+			ChannelName:  extra.ChannelName,
+			Author:       extra.Author,
+			Mention:      extra.Mention,
+			MentionRoles: extra.MentionRoles,
+			MentionAll:   extra.MentionAll,
+			MentionHere:  extra.MentionHere,
+		})
+	}
+
+	unregisterText := register(EventTypeTextMessage, adapter)
+	unregisterKMD := register(EventTypeKMDMessage, adapter)
+	return func() {
+		unregisterText()
+		unregisterKMD()
+	}
+}
+
+// onSystemEvent 注册指定子类型的系统事件处理器，subTypes 为空时接收全部系统事件
+func onSystemEvent(register registerFunc, logger Logger, subTypes []string, handler func(context.Context, *SystemEvent)) func() {
+	return register(MessageTypeSystem, func(ctx context.Context, event *Event) {
+		extra, err := event.ParseExtra()
+		if err != nil {
+			logger.Errorf("解析系统事件失败: %v", err)
+			return
+		}
+
+		matched := len(subTypes) == 0
+		for _, t := range subTypes {
+			if t == extra.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return
+		}
+
+		handler(ctx, &SystemEvent{
+			Event: event,
+			Type:  extra.Type,
+			Body:  extra.Body,
+		})
+	})
+}
+
+// onSystemBody 注册系统事件处理器，并把 body 解析为强类型结构
+func onSystemBody[T any](register registerFunc, logger Logger, subTypes []string, bind func(*T, *Event), handler func(context.Context, *T)) func() {
+	return onSystemEvent(register, logger, subTypes, func(ctx context.Context, sys *SystemEvent) {
+		v := new(T)
+		if len(sys.Body) > 0 {
+			if err := json.Unmarshal(sys.Body, v); err != nil {
+				logger.Errorf("解析系统事件 %s 失败: %v", sys.Type, err)
+				return
+			}
+		}
+		bind(v, sys.Event)
+		handler(ctx, v)
+	})
+}
+
+// OnTextMessage 注册文字/KMarkdown消息处理器
+func (r *EventRouter) OnTextMessage(handler func(context.Context, *TextMessageEvent)) func() {
+	return onTextMessage(r.OnEventCtx, r.logger, handler)
+}
+
+// OnSystemEvent 注册系统事件处理器，subTypes 为空时接收全部系统事件
+func (r *EventRouter) OnSystemEvent(handler func(context.Context, *SystemEvent), subTypes ...string) func() {
+	return onSystemEvent(r.OnEventCtx, r.logger, subTypes, handler)
+}
+
+// OnReactionAdded 注册添加回应处理器（含私聊）
+func (r *EventRouter) OnReactionAdded(handler func(context.Context, *ReactionEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventAddedReaction, SystemEventPrivateAddedReaction},
+		func(v *ReactionEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnReactionRemoved 注册取消回应处理器（含私聊）
+func (r *EventRouter) OnReactionRemoved(handler func(context.Context, *ReactionEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventDeletedReaction, SystemEventPrivateDeletedReaction},
+		func(v *ReactionEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnMessageUpdated 注册消息更新处理器（含私聊）
+func (r *EventRouter) OnMessageUpdated(handler func(context.Context, *MessageUpdatedEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventUpdatedMessage, SystemEventUpdatedPrivateMessage},
+		func(v *MessageUpdatedEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnMessageDeleted 注册消息删除处理器（含私聊）
+func (r *EventRouter) OnMessageDeleted(handler func(context.Context, *MessageDeletedEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventDeletedMessage, SystemEventDeletedPrivateMessage},
+		func(v *MessageDeletedEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnUserJoinedChannel 注册用户加入语音频道处理器
+func (r *EventRouter) OnUserJoinedChannel(handler func(context.Context, *UserChannelEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventJoinedChannel},
+		func(v *UserChannelEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnUserExitedChannel 注册用户退出语音频道处理器
+func (r *EventRouter) OnUserExitedChannel(handler func(context.Context, *UserChannelEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventExitedChannel},
+		func(v *UserChannelEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnUserJoinedGuild 注册用户加入服务器处理器
+func (r *EventRouter) OnUserJoinedGuild(handler func(context.Context, *UserGuildEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventJoinedGuild},
+		func(v *UserGuildEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnUserExitedGuild 注册用户退出服务器处理器
+func (r *EventRouter) OnUserExitedGuild(handler func(context.Context, *UserGuildEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventExitedGuild},
+		func(v *UserGuildEvent, e *Event) { v.Event = e }, handler)
+}
+
+// OnButtonClick 注册卡片按钮点击处理器
+func (r *EventRouter) OnButtonClick(handler func(context.Context, *ButtonClickEvent)) func() {
+	return onSystemBody(r.OnEventCtx, r.logger,
+		[]string{SystemEventMessageButtonClick},
+		func(v *ButtonClickEvent, e *Event) { v.Event = e }, handler)
+}
@@ -1,7 +1,11 @@
 package kook
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -22,6 +26,29 @@ type User struct {
 	VipAmp         bool   `json:"vip_amp"`
 	InvitedCount   int    `json:"invited_count"`
 	TagInfo        TagInfo `json:"tag_info"`
+	OS             string `json:"os"`              // 用户当前在线的平台
+	MobileVerified bool   `json:"mobile_verified"` // 是否已验证手机
+	ClientID       string `json:"client_id"`       // 机器人对应的应用ID（仅 user/me）
+	JoinedAt       int64  `json:"joined_at"`       // 加入服务器时间（毫秒，仅传入 guild_id 时返回）
+	ActiveTime     int64  `json:"active_time"`     // 活跃时间（毫秒，仅传入 guild_id 时返回）
+}
+
+// FullName 返回 "用户名#认证数字" 形式的完整用户名
+func (u *User) FullName() string {
+	if u.IdentifyNum == "" {
+		return u.Username
+	}
+	return u.Username + "#" + u.IdentifyNum
+}
+
+// HasRole 判断用户是否拥有指定角色（仅传入 guild_id 获取的用户信息包含角色）
+func (u *User) HasRole(roleID int) bool {
+	for _, id := range u.Roles {
+		if id == roleID {
+			return true
+		}
+	}
+	return false
 }
 
 // TagInfo 标签信息
@@ -35,7 +62,7 @@ type Guild struct {
 	ID                      string          `json:"id"`
 	Name                    string          `json:"name"`
 	Topic                   string          `json:"topic"`
-	UserID                  string          `json:"user_id"`
+	UserID                  string          `json:"user_id"` // 服务器主人ID
 	Icon                    string          `json:"icon"`
 	NotifyType              int             `json:"notify_type"`
 	Region                  string          `json:"region"`
@@ -54,6 +81,11 @@ type Guild struct {
 	Emojis                  []Emoji         `json:"emojis"`
 }
 
+// MasterID 返回服务器主人ID
+func (g *Guild) MasterID() string {
+	return g.UserID
+}
+
 // GuildFeature 服务器功能特性
 type GuildFeature struct {
 	Feature     string `json:"feature"`
@@ -112,7 +144,7 @@ type Message struct {
 	Content          string        `json:"content"`
 	Mention          []string      `json:"mention"`
 	MentionAll       bool          `json:"mention_all"`
-	MentionRoles     []string      `json:"mention_roles"`
+	MentionRoles     []int         `json:"mention_roles"`
 	MentionHere      bool          `json:"mention_here"`
 	Embeds           []interface{} `json:"embeds"`
 	Attachments      []Attachment  `json:"attachments"`
@@ -124,6 +156,10 @@ type Message struct {
 	ReadStatus       bool          `json:"read_status"`
 	Quote            *Quote        `json:"quote"`
 	MentionInfo      MentionInfo   `json:"mention_info"`
+	Nonce            string        `json:"nonce,omitempty"`
+	ChannelID        string        `json:"channel_id,omitempty"` // 所属频道ID（仅详情接口返回）
+	TargetID         string        `json:"target_id,omitempty"`  // 发送目标（频道ID或私聊用户ID），仅 SendMessage 回填
+	ChatCode         string        `json:"chat_code,omitempty"`  // 私聊会话Code，仅通过会话发送的私聊消息回填
 }
 
 // Attachment 附件信息
@@ -134,11 +170,95 @@ type Attachment struct {
 	Size int    `json:"size"`
 }
 
-// Reaction 反应信息
+// UnmarshalJSON 兼容列表接口与详情接口的字段差异：
+// attachments 为对象或数组，mention_roles 为数字或字符串，quote 为空字符串或对象
+func (m *Message) UnmarshalJSON(data []byte) error {
+	type messageAlias Message
+	aux := struct {
+		*messageAlias
+		Attachments  json.RawMessage `json:"attachments"`
+		MentionRoles json.RawMessage `json:"mention_roles"`
+		Quote        json.RawMessage `json:"quote"`
+	}{messageAlias: (*messageAlias)(m)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	attachments, err := decodeAttachments(aux.Attachments)
+	if err != nil {
+		return err
+	}
+	m.Attachments = attachments
+
+	roles, err := decodeFlexibleInts(aux.MentionRoles)
+	if err != nil {
+		return fmt.Errorf("解析mention_roles失败: %w", err)
+	}
+	m.MentionRoles = roles
+
+	m.Quote = nil
+	if quote := bytes.TrimSpace(aux.Quote); len(quote) > 0 && quote[0] == '{' {
+		var q Quote
+		if err := json.Unmarshal(quote, &q); err != nil {
+			return fmt.Errorf("解析引用消息失败: %w", err)
+		}
+		m.Quote = &q
+	}
+	return nil
+}
+
+// decodeFlexibleInts 解析元素为数字或数字字符串的数组
+func decodeFlexibleInts(raw json.RawMessage) ([]int, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, err
+	}
+
+	values := make([]int, 0, len(items))
+	for _, item := range items {
+		s := strings.Trim(strings.TrimSpace(string(item)), `"`)
+		if s == "" {
+			continue
+		}
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// ReactionCount 返回指定表情的回应人数，emoji 可以是表情ID或名称
+func (m *Message) ReactionCount(emoji string) int {
+	for _, r := range m.Reactions {
+		if r.Emoji.ID == emoji || r.Emoji.Name == emoji {
+			return r.Count
+		}
+	}
+	return 0
+}
+
+// TotalReactions 返回全部表情回应的人次总和
+func (m *Message) TotalReactions() int {
+	total := 0
+	for _, r := range m.Reactions {
+		total += r.Count
+	}
+	return total
+}
+
+// Reaction 反应信息（消息详情中按表情汇总的回应）
 type Reaction struct {
-	Emoji Emoji   `json:"emoji"`
-	Count int     `json:"count"`
-	Me    bool    `json:"me"`
+	Emoji Emoji `json:"emoji"` // 表情
+	Count int   `json:"count"` // 回应人数
+	Me    bool  `json:"me"`    // 当前用户是否回应过
 }
 
 // Quote 引用消息
@@ -205,6 +325,7 @@ type VoiceGateway struct {
 }
 
 // Event 事件信息
+// GuildID、ChannelID 等访问器从 target_id 与 extra 中解析；作者ID没有单独的访问器，直接读取 AuthorID 字段
 type Event struct {
 	ChannelType string      `json:"channel_type"`
 	Type        int         `json:"type"`
@@ -215,6 +336,8 @@ type Event struct {
 	MsgTimestamp int64      `json:"msg_timestamp"`
 	Nonce       string      `json:"nonce"`
 	Extra       interface{} `json:"extra"`
+
+	cache *eventCache // GuildID、ChannelID 等访问器的惰性解析结果，由 parseEvent 创建
 }
 
 
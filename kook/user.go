@@ -3,15 +3,22 @@ package kook
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 )
 
 // UserService 用户相关API服务
 type UserService struct {
 	client *Client
+
+	meMu        sync.Mutex
+	me          *User     // GetCurrentUser 缓存的当前用户信息
+	selfFetchAt time.Time // ignoreSelf 过滤器最近一次请求 user/me 的时间
 }
 
-// GetMe 获取当前用户信息
+// GetMe 获取当前用户信息（总是请求接口，并刷新 GetCurrentUser 的缓存）
 func (s *UserService) GetMe(ctx context.Context) (*User, error) {
 	resp, err := s.client.Get(ctx, "user/me", nil)
 	if err != nil {
@@ -23,7 +30,101 @@ func (s *UserService) GetMe(ctx context.Context) (*User, error) {
 		return nil, fmt.Errorf("解析用户信息失败: %w", err)
 	}
 
-	return &user, nil
+	s.meMu.Lock()
+	s.me = &user
+	s.meMu.Unlock()
+
+	cached := user
+	return &cached, nil
+}
+
+// GetCurrentUser 获取当前机器人信息，首次调用后结果会被缓存
+func (s *UserService) GetCurrentUser(ctx context.Context) (*User, error) {
+	s.meMu.Lock()
+	me := s.me
+	s.meMu.Unlock()
+
+	if me != nil {
+		cached := *me
+		return &cached, nil
+	}
+	return s.GetMe(ctx)
+}
+
+// InvalidateCurrentUser 清除 GetCurrentUser 的缓存
+func (s *UserService) InvalidateCurrentUser() {
+	s.meMu.Lock()
+	s.me = nil
+	s.meMu.Unlock()
+}
+
+// SelfID 获取当前机器人的用户ID（使用缓存）
+func (s *UserService) SelfID(ctx context.Context) (string, error) {
+	me, err := s.GetCurrentUser(ctx)
+	if err != nil {
+		return "", err
+	}
+	return me.ID, nil
+}
+
+// IsSelf 判断用户ID是否为当前机器人
+func (s *UserService) IsSelf(ctx context.Context, userID string) (bool, error) {
+	selfID, err := s.SelfID(ctx)
+	if err != nil {
+		return false, err
+	}
+	return userID != "" && userID == selfID, nil
+}
+
+// selfIDRetryInterval 过滤自身消息时，获取自身ID失败后再次请求 user/me 的最小间隔
+const selfIDRetryInterval = time.Minute
+
+// selfIDTimeout 过滤自身消息时请求 user/me 的超时时间
+const selfIDTimeout = 5 * time.Second
+
+// cachedSelfID 供事件过滤器使用，只读取缓存而不阻塞；尚未缓存时在后台请求 user/me，完成前返回空字符串
+func (s *UserService) cachedSelfID() string {
+	s.meMu.Lock()
+	defer s.meMu.Unlock()
+	if s.me != nil {
+		return s.me.ID
+	}
+	s.prefetchSelfIDLocked()
+	return ""
+}
+
+// prefetchSelfID 在后台请求 user/me 缓存机器人自身ID，供 ignoreSelf 过滤器使用
+// 已有缓存或处于 selfIDRetryInterval 重试间隔内时不做任何事
+func (s *UserService) prefetchSelfID() {
+	s.meMu.Lock()
+	defer s.meMu.Unlock()
+	if s.me == nil {
+		s.prefetchSelfIDLocked()
+	}
+}
+
+func (s *UserService) prefetchSelfIDLocked() {
+	if time.Since(s.selfFetchAt) < selfIDRetryInterval {
+		return
+	}
+	s.selfFetchAt = time.Now()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), selfIDTimeout)
+		defer cancel()
+		if _, err := s.GetMe(ctx); err != nil {
+			s.client.logger.Warnf("获取机器人自身ID失败，暂不过滤自身消息: %v", err)
+		}
+	}()
+}
+
+// ignoreSelfFilter WithIgnoreSelf 开启时安装的事件过滤器，丢弃作者为机器人自身的消息事件
+// 自身ID由 prefetchSelfID 提前获取，获取完成前不过滤
+func (c *Client) ignoreSelfFilter(event *Event) bool {
+	if event.Type == MessageTypeSystem || event.AuthorID == "" {
+		return true
+	}
+	selfID := c.User.cachedSelfID()
+	return selfID == "" || event.AuthorID != selfID
 }
 
 // GetUser 获取指定用户信息
@@ -97,6 +198,7 @@ func (s *UserService) UpdateUserInfo(ctx context.Context, params UpdateUserParam
 	if err != nil {
 		return nil, err
 	}
+	s.InvalidateCurrentUser()
 
 	var user User
 	if err := json.Unmarshal(resp.Data, &user); err != nil {
@@ -156,16 +258,56 @@ func (s *UserService) GetBlockedUsers(ctx context.Context) ([]User, error) {
 	return users, nil
 }
 
-// SetOnline 上线机器人（仅限Webhook使用）
+// SetOnline 上线机器人
+// Webhook 机器人调用 user/online；通过 SetOffline 下线的 WebSocket 机器人以重新连接所有已下线网关的方式上线，
+// 连接受 ctx 控制，失败的网关保持下线状态，可再次调用 SetOnline 重试
 func (s *UserService) SetOnline(ctx context.Context) error {
-	_, err := s.client.Post(ctx, "user/online", nil)
-	return err
+	var suspended []*WebSocketClient
+	for _, ws := range s.client.trackedGateways() {
+		if ws.suspended.Load() {
+			suspended = append(suspended, ws)
+		}
+	}
+	if len(suspended) == 0 {
+		_, err := s.client.Post(ctx, "user/online", nil)
+		return err
+	}
+
+	var errs []error
+	for _, ws := range suspended {
+		if err := ws.resume(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// SetOffline 下线机器人（仅限Webhook使用）
+// SetOffline 下线机器人，适用于维护期间显示离线
+// 所有已连接的网关会先断开并暂停自动重连（心跳与重连不会把机器人重新拉上线），直到调用 SetOnline
 func (s *UserService) SetOffline(ctx context.Context) error {
-	_, err := s.client.Post(ctx, "user/offline", nil)
-	return err
+	var suspended []*WebSocketClient
+	for _, ws := range s.client.trackedGateways() {
+		if ws.suspend() {
+			suspended = append(suspended, ws)
+		}
+	}
+	if _, err := s.client.Post(ctx, "user/offline", nil); err != nil {
+		// 下线失败时恢复已断开的网关；ctx 可能已经结束，改用独立的握手超时
+		resumeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultHandshakeTimeout)
+		defer cancel()
+		for _, ws := range suspended {
+			if resumeErr := ws.resume(resumeCtx); resumeErr != nil {
+				s.client.logger.WithError(resumeErr).Errorf("下线失败后恢复网关连接失败")
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// Offline 强制下线机器人，行为同 SetOffline
+func (s *UserService) Offline(ctx context.Context) error {
+	return s.SetOffline(ctx)
 }
 
 // GetOnlineStatus 获取机器人在线状态
@@ -0,0 +1,42 @@
+package kook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIgnoreSelfFilterDoesNotBlock 过滤器不等待 user/me 请求，自身ID在后台获取完成后才开始过滤
+func TestIgnoreSelfFilterDoesNotBlock(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		w.Write([]byte(`{"code":0,"message":"","data":{"id":"bot"}}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewClient("token", WithBaseURL(server.URL+"/api"), WithLogger(NopLogger()), WithoutRateLimit(), WithIgnoreSelf(true))
+	event := &Event{Type: MessageTypeText, AuthorID: "bot"}
+
+	done := make(chan bool)
+	go func() { done <- client.ignoreSelfFilter(event) }()
+	select {
+	case accepted := <-done:
+		assert.True(t, accepted, "自身ID获取完成前不过滤")
+	case <-time.After(time.Second):
+		t.Fatal("过滤器阻塞在 user/me 请求上")
+	}
+	assert.True(t, client.ignoreSelfFilter(event))
+
+	release <- struct{}{}
+	assert.Eventually(t, func() bool { return !client.ignoreSelfFilter(event) }, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, client.ignoreSelfFilter(&Event{Type: MessageTypeText, AuthorID: "user"}))
+	assert.Equal(t, int32(1), requests.Load(), "重试间隔内只请求一次 user/me")
+}
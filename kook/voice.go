@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 // VoiceService 语音相关API服务
@@ -53,24 +55,26 @@ func (s *VoiceService) GetVoiceChannelUsers(ctx context.Context, channelID strin
 	return nil, fmt.Errorf("KOOK v3 官方接口未提供 voice/users；请改用 GetJoinedVoiceChannels")
 }
 
-// MuteUser 静音用户
-func (s *VoiceService) MuteUser(ctx context.Context, channelID, userID string) error {
-	return fmt.Errorf("KOOK v3 官方接口未提供 voice/mute")
+// MuteGuildUser 服务器静音：禁止用户收听语音（guild-mute/create，type=2，对应 VoiceUser.Muted）
+// 取代旧版按频道ID调用、始终返回错误的 MuteUser，参数改为服务器ID
+func (s *VoiceService) MuteGuildUser(ctx context.Context, guildID, userID string) error {
+	return s.client.Guild.MuteUser(ctx, guildID, userID, MuteTypeHeadset)
 }
 
-// UnmuteUser 取消静音用户
-func (s *VoiceService) UnmuteUser(ctx context.Context, channelID, userID string) error {
-	return fmt.Errorf("KOOK v3 官方接口未提供 voice/unmute")
+// UnmuteGuildUser 解除服务器静音
+func (s *VoiceService) UnmuteGuildUser(ctx context.Context, guildID, userID string) error {
+	return s.client.Guild.UnmuteUser(ctx, guildID, userID, MuteTypeHeadset)
 }
 
-// DeafenUser 闭麦用户
-func (s *VoiceService) DeafenUser(ctx context.Context, channelID, userID string) error {
-	return fmt.Errorf("KOOK v3 官方接口未提供 voice/deafen")
+// DeafenGuildUser 服务器闭麦：禁止用户使用麦克风（guild-mute/create，type=1，对应 VoiceUser.Deafened）
+// 取代旧版按频道ID调用、始终返回错误的 DeafenUser，参数改为服务器ID
+func (s *VoiceService) DeafenGuildUser(ctx context.Context, guildID, userID string) error {
+	return s.client.Guild.MuteUser(ctx, guildID, userID, MuteTypeMic)
 }
 
-// UndeafenUser 取消闭麦用户
-func (s *VoiceService) UndeafenUser(ctx context.Context, channelID, userID string) error {
-	return fmt.Errorf("KOOK v3 官方接口未提供 voice/undeafen")
+// UndeafenGuildUser 解除服务器闭麦
+func (s *VoiceService) UndeafenGuildUser(ctx context.Context, guildID, userID string) error {
+	return s.client.Guild.UnmuteUser(ctx, guildID, userID, MuteTypeMic)
 }
 
 // GetJoinedVoiceChannels 获取机器人已加入的语音频道列表
@@ -109,6 +113,62 @@ type VoiceConnectionInfo struct {
 	Token      string `json:"token"`       // 语音令牌
 	Endpoint   string `json:"endpoint"`    // 连接端点
 	SessionID  string `json:"session_id"`  // 会话ID
+
+	// 以下为 voice/join 返回的 RTP 推流参数
+	IP        string `json:"ip"`         // 推流地址
+	Port      int    `json:"port"`       // RTP 端口
+	RTCPMux   bool   `json:"rtcp_mux"`   // RTCP 是否与 RTP 复用同一端口
+	RTCPPort  int    `json:"rtcp_port"`  // RTCP 端口（RTCPMux 为 false 时使用）
+	Bitrate   int    `json:"bitrate"`    // 推荐码率（bps）
+	AudioSSRC uint32 `json:"audio_ssrc"` // 音频 SSRC
+	AudioPT   uint8  `json:"audio_pt"`   // 音频 payload type
+}
+
+// UnmarshalJSON 兼容端口、码率、SSRC、payload type 以字符串或数字返回的情况
+func (i *VoiceConnectionInfo) UnmarshalJSON(data []byte) error {
+	type plain VoiceConnectionInfo
+	var raw struct {
+		*plain
+		Port      json.RawMessage `json:"port"`
+		RTCPPort  json.RawMessage `json:"rtcp_port"`
+		Bitrate   json.RawMessage `json:"bitrate"`
+		AudioSSRC json.RawMessage `json:"audio_ssrc"`
+		AudioPT   json.RawMessage `json:"audio_pt"`
+	}
+	raw.plain = (*plain)(i)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	fields := []struct {
+		name string
+		raw  json.RawMessage
+		bits int
+		set  func(uint64)
+	}{
+		{"port", raw.Port, 16, func(v uint64) { i.Port = int(v) }},
+		{"rtcp_port", raw.RTCPPort, 16, func(v uint64) { i.RTCPPort = int(v) }},
+		{"bitrate", raw.Bitrate, 32, func(v uint64) { i.Bitrate = int(v) }},
+		{"audio_ssrc", raw.AudioSSRC, 32, func(v uint64) { i.AudioSSRC = uint32(v) }},
+		{"audio_pt", raw.AudioPT, 7, func(v uint64) { i.AudioPT = uint8(v) }},
+	}
+	for _, f := range fields {
+		v, err := parseFlexibleUint(f.raw, f.bits)
+		if err != nil {
+			return fmt.Errorf("解析%s失败: %w", f.name, err)
+		}
+		f.set(v)
+	}
+	return nil
+}
+
+// parseFlexibleUint 解析数字或数字字符串，空值返回 0
+func parseFlexibleUint(raw json.RawMessage, bits int) (uint64, error) {
+	s := strings.Trim(strings.TrimSpace(string(raw)), `"`)
+	if s == "" || s == "null" {
+		return 0, nil
+	}
+	return strconv.ParseUint(s, 10, bits)
 }
 
 // VoiceUser 语音频道用户
@@ -0,0 +1,274 @@
+package kook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Opus 推流参数
+const (
+	// OpusSampleRate Opus 采样率
+	OpusSampleRate = 48000
+	// OpusFrameDuration 每个 Opus 帧的时长
+	OpusFrameDuration = 20 * time.Millisecond
+	// OpusFrameSamples 每个 20ms 帧的采样数
+	OpusFrameSamples = OpusSampleRate / 1000 * 20
+
+	// defaultAudioPT KOOK 默认的 Opus payload type
+	defaultAudioPT = 111
+	// rtcpReportInterval RTCP 发送者报告的发送间隔
+	rtcpReportInterval = 5 * time.Second
+)
+
+// ErrVoiceConnectionClosed 语音连接已关闭
+var ErrVoiceConnectionClosed = errors.New("语音连接已关闭")
+
+// VoiceConnection 语音频道的 RTP 推流连接
+// 通过 voice/join 获得推流地址后，以 RTP over UDP 发送 Opus 帧，并定期发送 RTCP 发送者报告
+type VoiceConnection struct {
+	ChannelID string
+	Info      *VoiceConnectionInfo
+
+	service  *VoiceService
+	rtpConn  *net.UDPConn
+	rtcpConn *net.UDPConn
+
+	mu          sync.Mutex
+	sequence    uint16
+	timestamp   uint32
+	ssrc        uint32
+	payloadType uint8
+	started     bool
+	packets     uint32
+	octets      uint32
+	closed      bool
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Connect 加入语音频道并建立 RTP 推流连接
+func (s *VoiceService) Connect(ctx context.Context, channelID string) (*VoiceConnection, error) {
+	info, err := s.JoinVoiceChannel(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	vc, err := NewVoiceConnection(info)
+	if err != nil {
+		// 推流连接建立失败时释放频道占用
+		if leaveErr := s.LeaveVoiceChannel(ctx, channelID); leaveErr != nil {
+			s.client.logger.WithError(leaveErr).Warnf("离开语音频道失败")
+		}
+		return nil, err
+	}
+
+	vc.ChannelID = channelID
+	vc.service = s
+	return vc, nil
+}
+
+// NewVoiceConnection 根据 voice/join 返回的信息建立 RTP 推流连接
+func NewVoiceConnection(info *VoiceConnectionInfo) (*VoiceConnection, error) {
+	if info == nil {
+		return nil, fmt.Errorf("语音连接信息不能为空")
+	}
+	if info.IP == "" || info.Port == 0 {
+		return nil, fmt.Errorf("语音连接信息缺少推流地址")
+	}
+
+	rtpConn, err := dialUDP(info.IP, info.Port)
+	if err != nil {
+		return nil, fmt.Errorf("连接RTP端口失败: %w", err)
+	}
+
+	var rtcpConn *net.UDPConn
+	if !info.RTCPMux && info.RTCPPort != 0 {
+		rtcpConn, err = dialUDP(info.IP, info.RTCPPort)
+		if err != nil {
+			rtpConn.Close()
+			return nil, fmt.Errorf("连接RTCP端口失败: %w", err)
+		}
+	}
+
+	payloadType := info.AudioPT
+	if payloadType == 0 {
+		payloadType = defaultAudioPT
+	}
+
+	ssrc := info.AudioSSRC
+	if ssrc == 0 {
+		ssrc = randomUint32()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	vc := &VoiceConnection{
+		Info:        info,
+		rtpConn:     rtpConn,
+		rtcpConn:    rtcpConn,
+		sequence:    uint16(randomUint32()),
+		timestamp:   randomUint32(),
+		ssrc:        ssrc,
+		payloadType: payloadType,
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+
+	go vc.reportLoop(ctx)
+	return vc, nil
+}
+
+// SendOpus 发送一个 20ms 的 Opus 帧
+func (vc *VoiceConnection) SendOpus(frame []byte) error {
+	return vc.SendOpusSamples(frame, OpusFrameSamples)
+}
+
+// SendOpusSamples 发送一个 Opus 帧，samples 为该帧包含的采样数（48kHz）
+// 发送方负责按帧时长控制发送节奏
+func (vc *VoiceConnection) SendOpusSamples(frame []byte, samples uint32) error {
+	if len(frame) == 0 {
+		return fmt.Errorf("Opus帧不能为空")
+	}
+
+	vc.mu.Lock()
+	if vc.closed {
+		vc.mu.Unlock()
+		return ErrVoiceConnectionClosed
+	}
+
+	packet := make([]byte, 12+len(frame))
+	packet[0] = 0x80 // V=2, P=0, X=0, CC=0
+	packet[1] = vc.payloadType & 0x7f
+	if !vc.started {
+		packet[1] |= 0x80 // 首个包设置 marker
+		vc.started = true
+	}
+	binary.BigEndian.PutUint16(packet[2:4], vc.sequence)
+	binary.BigEndian.PutUint32(packet[4:8], vc.timestamp)
+	binary.BigEndian.PutUint32(packet[8:12], vc.ssrc)
+	copy(packet[12:], frame)
+
+	vc.sequence++
+	vc.timestamp += samples
+	vc.packets++
+	vc.octets += uint32(len(frame))
+	conn := vc.rtpConn
+	vc.mu.Unlock()
+
+	if _, err := conn.Write(packet); err != nil {
+		return fmt.Errorf("发送RTP包失败: %w", err)
+	}
+	return nil
+}
+
+// Close 关闭推流连接（不会离开语音频道）
+func (vc *VoiceConnection) Close() error {
+	vc.mu.Lock()
+	if vc.closed {
+		vc.mu.Unlock()
+		return nil
+	}
+	vc.closed = true
+	vc.mu.Unlock()
+
+	vc.cancel()
+	<-vc.done
+
+	err := vc.rtpConn.Close()
+	if vc.rtcpConn != nil {
+		if rtcpErr := vc.rtcpConn.Close(); err == nil {
+			err = rtcpErr
+		}
+	}
+	return err
+}
+
+// Disconnect 关闭推流连接并离开语音频道
+func (vc *VoiceConnection) Disconnect(ctx context.Context) error {
+	closeErr := vc.Close()
+	if vc.service == nil || vc.ChannelID == "" {
+		return closeErr
+	}
+	if err := vc.service.LeaveVoiceChannel(ctx, vc.ChannelID); err != nil {
+		return err
+	}
+	return closeErr
+}
+
+// reportLoop 定期发送 RTCP 发送者报告
+func (vc *VoiceConnection) reportLoop(ctx context.Context) {
+	defer close(vc.done)
+
+	ticker := time.NewTicker(rtcpReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = vc.sendSenderReport()
+		}
+	}
+}
+
+// sendSenderReport 发送 RTCP SR（RFC 3550 6.4.1），尚未发送音频时跳过
+func (vc *VoiceConnection) sendSenderReport() error {
+	vc.mu.Lock()
+	if vc.closed || vc.packets == 0 {
+		vc.mu.Unlock()
+		return nil
+	}
+
+	report := make([]byte, 28)
+	report[0] = 0x80 // V=2, P=0, RC=0
+	report[1] = 200  // PT=SR
+	binary.BigEndian.PutUint16(report[2:4], 6)
+	binary.BigEndian.PutUint32(report[4:8], vc.ssrc)
+	binary.BigEndian.PutUint64(report[8:16], ntpTimestamp(time.Now()))
+	binary.BigEndian.PutUint32(report[16:20], vc.timestamp)
+	binary.BigEndian.PutUint32(report[20:24], vc.packets)
+	binary.BigEndian.PutUint32(report[24:28], vc.octets)
+
+	conn := vc.rtpConn
+	if vc.rtcpConn != nil {
+		conn = vc.rtcpConn
+	}
+	vc.mu.Unlock()
+
+	_, err := conn.Write(report)
+	return err
+}
+
+// dialUDP 建立 UDP 连接
+func dialUDP(host string, port int) (*net.UDPConn, error) {
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	return net.DialUDP("udp", nil, addr)
+}
+
+// ntpTimestamp 将时间转换为 64 位 NTP 时间戳
+func ntpTimestamp(t time.Time) uint64 {
+	const ntpEpochOffset = 2208988800 // 1900-01-01 到 1970-01-01 的秒数
+	seconds := uint64(t.Unix()) + ntpEpochOffset
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	return seconds<<32 | fraction
+}
+
+// randomUint32 生成随机的 32 位整数，用于初始序号与时间戳
+func randomUint32() uint32 {
+	var b [4]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return uint32(time.Now().UnixNano())
+	}
+	return binary.BigEndian.Uint32(b[:])
+}
@@ -0,0 +1,285 @@
+package kook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// voiceKeepAliveInterval 播放期间续期语音频道占用的间隔
+const voiceKeepAliveInterval = 40 * time.Second
+
+// PlaybackEndAction 播放结束后的行为
+type PlaybackEndAction int
+
+const (
+	// PlaybackEndLeave 播放结束后离开语音频道（默认）
+	PlaybackEndLeave PlaybackEndAction = iota
+	// PlaybackEndKeepAlive 播放结束后续期频道占用并留在频道中
+	PlaybackEndKeepAlive
+)
+
+// AudioPlayOptions 音频播放选项
+type AudioPlayOptions struct {
+	EndAction  PlaybackEndAction // 播放结束后的行为
+	FFmpegPath string            // ffmpeg 可执行文件路径，默认从 PATH 查找
+	Bitrate    int               // Opus 码率（bps），默认使用 voice/join 返回的码率
+}
+
+// AudioPlayer 音频播放句柄，可暂停、恢复、停止
+type AudioPlayer struct {
+	vc     *VoiceConnection
+	cancel context.CancelFunc
+
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{}
+
+	done chan struct{}
+	err  error
+}
+
+// PlayAudioFile 将音频文件转码为 48kHz Opus 并推送到语音频道，阻塞直到播放结束或 ctx 取消
+// 播放结束后离开语音频道，需要 ffmpeg（支持 libopus）
+func (s *VoiceService) PlayAudioFile(ctx context.Context, channelID, path string) error {
+	player, err := s.StartAudioFile(ctx, channelID, path, nil)
+	if err != nil {
+		return err
+	}
+	return player.Wait()
+}
+
+// StartAudioFile 在后台播放音频文件并返回播放句柄，opts 可为 nil
+// ctx 取消或调用 Stop 时停止播放，之后按 EndAction 离开或续期频道
+func (s *VoiceService) StartAudioFile(ctx context.Context, channelID, path string, opts *AudioPlayOptions) (*AudioPlayer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("音频文件路径不能为空")
+	}
+	if opts == nil {
+		opts = &AudioPlayOptions{}
+	}
+
+	ffmpegPath := opts.FFmpegPath
+	if ffmpegPath == "" {
+		ffmpegPath = "ffmpeg"
+	}
+	ffmpegPath, err := exec.LookPath(ffmpegPath)
+	if err != nil {
+		return nil, fmt.Errorf("未找到ffmpeg: %w", err)
+	}
+
+	vc, err := s.Connect(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	bitrate := opts.Bitrate
+	if bitrate <= 0 {
+		bitrate = vc.Info.Bitrate
+	}
+	if bitrate <= 0 {
+		bitrate = 128000
+	}
+
+	playCtx, cancel := context.WithCancel(ctx)
+	cmd := exec.CommandContext(playCtx, ffmpegPath,
+		"-hide_banner", "-loglevel", "error",
+		"-i", path,
+		"-map", "0:a:0",
+		"-c:a", "libopus",
+		"-ar", strconv.Itoa(OpusSampleRate),
+		"-ac", "2",
+		"-b:a", strconv.Itoa(bitrate),
+		"-frame_duration", "20",
+		"-application", "audio",
+		"-f", "ogg", "pipe:1",
+	)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		cancel()
+		s.finishPlayback(vc, opts.EndAction)
+		return nil, fmt.Errorf("创建ffmpeg输出管道失败: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		cancel()
+		s.finishPlayback(vc, opts.EndAction)
+		return nil, fmt.Errorf("启动ffmpeg失败: %w", err)
+	}
+
+	player := &AudioPlayer{
+		vc:     vc,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(player.done)
+		defer cancel()
+
+		go s.keepAliveLoop(playCtx, channelID)
+
+		err := player.stream(playCtx, newOggOpusReader(stdout))
+		if err != nil {
+			cancel()
+		}
+		// 读取中断时需要排空管道，否则 ffmpeg 可能阻塞在写出上
+		_, _ = io.Copy(io.Discard, stdout)
+		waitErr := cmd.Wait()
+
+		switch {
+		case err != nil:
+		case playCtx.Err() != nil:
+			// 主动停止或 ctx 取消不视为错误
+		case waitErr != nil:
+			err = fmt.Errorf("ffmpeg转码失败: %w", waitErr)
+		}
+		player.err = err
+
+		s.finishPlayback(vc, opts.EndAction)
+	}()
+
+	return player, nil
+}
+
+// stream 按 20ms 节奏推送 Opus 包
+func (p *AudioPlayer) stream(ctx context.Context, reader *oggOpusReader) error {
+	next := time.Now()
+	for {
+		packet, err := reader.ReadPacket()
+		if err != nil {
+			if errors.Is(err, io.EOF) || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("读取音频数据失败: %w", err)
+		}
+
+		waited, err := p.waitIfPaused(ctx)
+		if err != nil {
+			return nil
+		}
+		if waited {
+			next = time.Now()
+		}
+
+		if delay := time.Until(next); delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil
+			case <-timer.C:
+			}
+		}
+
+		if err := p.vc.SendOpus(packet); err != nil {
+			return err
+		}
+		next = next.Add(OpusFrameDuration)
+	}
+}
+
+// waitIfPaused 暂停时阻塞直到恢复，返回是否发生过等待
+func (p *AudioPlayer) waitIfPaused(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	if !p.paused {
+		p.mu.Unlock()
+		return false, nil
+	}
+	resume := p.resume
+	p.mu.Unlock()
+
+	select {
+	case <-resume:
+		return true, nil
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// Pause 暂停播放
+func (p *AudioPlayer) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.paused {
+		p.paused = true
+		p.resume = make(chan struct{})
+	}
+}
+
+// Resume 恢复播放
+func (p *AudioPlayer) Resume() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.paused {
+		p.paused = false
+		close(p.resume)
+	}
+}
+
+// Paused 返回是否处于暂停状态
+func (p *AudioPlayer) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Stop 停止播放并等待收尾完成
+func (p *AudioPlayer) Stop() error {
+	p.cancel()
+	return p.Wait()
+}
+
+// Done 返回播放结束时关闭的通道
+func (p *AudioPlayer) Done() <-chan struct{} {
+	return p.done
+}
+
+// Wait 等待播放结束，返回播放过程中的错误
+func (p *AudioPlayer) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// keepAliveLoop 播放期间定期续期频道占用
+func (s *VoiceService) keepAliveLoop(ctx context.Context, channelID string) {
+	ticker := time.NewTicker(voiceKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.KeepAliveVoiceChannel(ctx, channelID); err != nil && ctx.Err() == nil {
+				s.client.logger.WithError(err).Warnf("续期语音频道失败")
+			}
+		}
+	}
+}
+
+// finishPlayback 播放结束后按 EndAction 离开或续期频道
+func (s *VoiceService) finishPlayback(vc *VoiceConnection, action PlaybackEndAction) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if action == PlaybackEndKeepAlive {
+		if err := vc.Close(); err != nil {
+			s.client.logger.WithError(err).Warnf("关闭语音推流连接失败")
+		}
+		if err := s.KeepAliveVoiceChannel(ctx, vc.ChannelID); err != nil {
+			s.client.logger.WithError(err).Warnf("续期语音频道失败")
+		}
+		return
+	}
+
+	if err := vc.Disconnect(ctx); err != nil {
+		s.client.logger.WithError(err).Warnf("离开语音频道失败")
+	}
+}
@@ -4,24 +4,247 @@ import (
 	"bytes"
 	"compress/gzip"
 	"compress/zlib"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
 )
 
 // WebhookHandler Webhook处理器
 type WebhookHandler struct {
-	client        *Client
-	encryptKey    string
-	verifyToken   string
-	eventHandlers map[int][]EventHandler
-	mu            sync.RWMutex
+	*EventRouter
+
+	client      *Client
+	encryptKey  string
+	verifyToken string
+	encryption  webhookEncryption
+
+	dedupWindow  int
+	dedup        *snDeduplicator
+	syncDispatch bool
+	maxEventAge  time.Duration
+	rawBodyHook  func(raw, decoded []byte)
+
+	responseWriter func(w http.ResponseWriter, result HandleResult)
+	trustedProxies []netip.Prefix
+
+	decoders       map[string]ContentDecoder
+	maxBodySize    int64
+	maxDecodedSize int64
+
+	// 验证失败计数，按原因区分
+	rejectedDecode  atomic.Uint64
+	rejectedDecrypt atomic.Uint64
+	rejectedToken   atomic.Uint64
+	rejectedExpired atomic.Uint64
+	rejectedLarge   atomic.Uint64
+
+	serverMu sync.Mutex
+	server   *http.Server
+}
+
+// WebhookOption Webhook处理器配置选项
+type WebhookOption func(*WebhookHandler)
+
+// WithSyncDispatch 设置是否同步分发事件
+// 同步模式下按注册顺序依次调用处理器，全部执行完毕后才返回 HTTP 响应；
+// 某个处理器 panic 或（通过 OnEventErr 注册的处理器）返回错误会被记录，但不影响后续处理器。默认异步分发
+func WithSyncDispatch(enabled bool) WebhookOption {
+	return func(wh *WebhookHandler) {
+		wh.syncDispatch = enabled
+	}
+}
+
+// WithResponseWriter 自定义 Webhook 的 HTTP 响应，用于对接要求特定 ack 格式的反代或监控
+// 成功、失败与 challenge 请求都会调用该钩子，challenge 请求需要把 result.Challenge 按 KOOK 要求回传，
+// 否则回调地址验证会失败；不需要定制的情况可调用 DefaultWebhookResponse
+func WithResponseWriter(fn func(w http.ResponseWriter, result HandleResult)) WebhookOption {
+	return func(wh *WebhookHandler) {
+		wh.responseWriter = fn
+	}
+}
+
+// WithDedupWindow 设置按 sn 去重的窗口大小（默认1024），0 表示关闭去重
+func WithDedupWindow(size int) WebhookOption {
+	return func(wh *WebhookHandler) {
+		wh.dedupWindow = size
+	}
+}
+
+// DefaultMaxEventAge Webhook事件时间戳的默认有效窗口
+const DefaultMaxEventAge = 5 * time.Minute
+
+// WithMaxEventAge 设置事件时间戳（msg_timestamp）的最大允许偏差，用于拒绝重放的旧请求
+// 默认 5 分钟，0 表示不校验
+func WithMaxEventAge(d time.Duration) WebhookOption {
+	return func(wh *WebhookHandler) {
+		if d >= 0 {
+			wh.maxEventAge = d
+		}
+	}
+}
+
+// WithRawBodyHook 设置原始请求体钩子，便于调试加密Webhook
+// raw 为收到的原始字节，decoded 为解压、解密后的字节；解压或解密失败时 decoded 为 nil。
+// 钩子在 JSON 解析之前调用，其中的 panic 会被恢复，不影响后续处理
+func WithRawBodyHook(hook func(raw, decoded []byte)) WebhookOption {
+	return func(wh *WebhookHandler) {
+		wh.rawBodyHook = hook
+	}
+}
+
+// DefaultMaxBodySize 原始请求体的默认大小上限
+const DefaultMaxBodySize = 1 << 20
+
+// WithMaxBodySize 设置原始请求体的大小上限（默认 1MB），超限返回 413，0 表示不限制
+func WithMaxBodySize(n int64) WebhookOption {
+	return func(wh *WebhookHandler) {
+		if n >= 0 {
+			wh.maxBodySize = n
+		}
+	}
+}
+
+// WithMaxDecodedBodySize 设置解压后请求体的大小上限（默认 8MB），用于防御解压炸弹，超限返回 413，0 表示不限制
+func WithMaxDecodedBodySize(n int64) WebhookOption {
+	return func(wh *WebhookHandler) {
+		if n >= 0 {
+			wh.maxDecodedSize = n
+		}
+	}
+}
+
+// ContentDecoder 请求体解码器，返回的 ReadCloser 在读取结束后会被关闭以释放资源
+type ContentDecoder func(r io.Reader) (io.ReadCloser, error)
+
+// WithContentDecoder 注册额外的 Content-Encoding 解码器（不区分大小写），也可覆盖内置的 gzip、deflate、zstd
+// 例如反代使用 brotli 转发时：
+//
+//	kook.WithContentDecoder("br", func(r io.Reader) (io.ReadCloser, error) {
+//		return io.NopCloser(brotli.NewReader(r)), nil
+//	})
+func WithContentDecoder(encoding string, decoder ContentDecoder) WebhookOption {
+	return func(wh *WebhookHandler) {
+		if decoder == nil {
+			return
+		}
+		if wh.decoders == nil {
+			wh.decoders = make(map[string]ContentDecoder)
+		}
+		wh.decoders[strings.ToLower(strings.TrimSpace(encoding))] = decoder
+	}
+}
+
+// WithWebhookRouter 使用指定的事件路由器，可与 WebSocketClient 共享同一套事件处理器
+func WithWebhookRouter(router *EventRouter) WebhookOption {
+	return func(wh *WebhookHandler) {
+		if router != nil {
+			wh.EventRouter = router
+		}
+	}
+}
+
+// webhookEncryption Webhook消息加密模式
+type webhookEncryption int
+
+const (
+	encryptionAuto webhookEncryption = iota // 根据 encrypt 字段自动判断（默认）
+	encryptionOn                            // 必须是加密消息
+	encryptionOff                           // 明文模式，不尝试解密
+)
+
+// WithEncryption 明确声明Webhook是否启用加密
+// 启用时拒绝未加密的消息；关闭时跳过解密直接解析，避免明文中恰好含有 encrypt 字段被误判。
+// 未设置时根据消息是否包含 encrypt 字段自动判断
+func WithEncryption(enabled bool) WebhookOption {
+	return func(wh *WebhookHandler) {
+		if enabled {
+			wh.encryption = encryptionOn
+		} else {
+			wh.encryption = encryptionOff
+		}
+	}
+}
+
+// Webhook请求被拒绝的原因，用于 WebhookMetricsCollector 与 RejectedStats
+const (
+	WebhookRejectDecode   = "decode"       // 解压或解析请求体失败
+	WebhookRejectDecrypt  = "decrypt"      // 解密失败或加密模式不符
+	WebhookRejectToken    = "verify_token" // verify_token 不匹配
+	WebhookRejectExpired  = "expired"      // 事件时间戳超出允许窗口
+	WebhookRejectTooLarge = "too_large"    // 请求体或解压结果超过大小限制
+)
+
+// WebhookMetricsCollector 可选的Webhook指标接口
+// 通过 WithMetrics 设置的收集器若同时实现了该接口，每次请求验证失败时都会被调用，便于发现刷 token 等攻击
+type WebhookMetricsCollector interface {
+	IncWebhookRejected(reason string)
+}
+
+// WebhookRejectedStats Webhook验证失败计数
+type WebhookRejectedStats struct {
+	Decode   uint64 // 解压或解析请求体失败
+	Decrypt  uint64 // 解密失败
+	Token    uint64 // verify_token 不匹配
+	Expired  uint64 // 事件时间戳过期
+	TooLarge uint64 // 请求体超过大小限制
+}
+
+// Total 返回验证失败总数
+func (s WebhookRejectedStats) Total() uint64 {
+	return s.Decode + s.Decrypt + s.Token + s.Expired + s.TooLarge
+}
+
+// RejectedStats 返回自创建以来各类验证失败的次数
+func (wh *WebhookHandler) RejectedStats() WebhookRejectedStats {
+	return WebhookRejectedStats{
+		Decode:   wh.rejectedDecode.Load(),
+		Decrypt:  wh.rejectedDecrypt.Load(),
+		Token:    wh.rejectedToken.Load(),
+		Expired:  wh.rejectedExpired.Load(),
+		TooLarge: wh.rejectedLarge.Load(),
+	}
+}
+
+// reject 记录一次验证失败
+func (wh *WebhookHandler) reject(reason string) {
+	switch reason {
+	case WebhookRejectDecode:
+		wh.rejectedDecode.Add(1)
+	case WebhookRejectDecrypt:
+		wh.rejectedDecrypt.Add(1)
+	case WebhookRejectToken:
+		wh.rejectedToken.Add(1)
+	case WebhookRejectExpired:
+		wh.rejectedExpired.Add(1)
+	case WebhookRejectTooLarge:
+		wh.rejectedLarge.Add(1)
+	}
+	if m, ok := wh.client.metrics.(WebhookMetricsCollector); ok {
+		m.IncWebhookRejected(reason)
+	}
+}
+
+// verifyTokenEqual 以恒定时间比较 verify_token
+// 先对两边取 SHA-256 再比较，长度不同时耗时也与长度无关
+func verifyTokenEqual(got, want string) bool {
+	a := sha256.Sum256([]byte(got))
+	b := sha256.Sum256([]byte(want))
+	return subtle.ConstantTimeCompare(a[:], b[:]) == 1
 }
 
 // WebhookMessage Webhook消息结构
@@ -36,111 +259,271 @@ type encryptedWebhookMessage struct {
 }
 
 type webhookPayloadMeta struct {
-	ChannelType string `json:"channel_type"`
-	VerifyToken string `json:"verify_token"`
-	Challenge   string `json:"challenge"`
+	ChannelType  string `json:"channel_type"`
+	VerifyToken  string `json:"verify_token"`
+	Challenge    string `json:"challenge"`
+	MsgTimestamp int64  `json:"msg_timestamp"`
 }
 
 // NewWebhookHandler 创建新的Webhook处理器
-func NewWebhookHandler(client *Client, encryptKey, verifyToken string) *WebhookHandler {
-	return &WebhookHandler{
-		client:        client,
-		encryptKey:    encryptKey,
-		verifyToken:   verifyToken,
-		eventHandlers: make(map[int][]EventHandler),
+func NewWebhookHandler(client *Client, encryptKey, verifyToken string, opts ...WebhookOption) *WebhookHandler {
+	wh := &WebhookHandler{
+		EventRouter: NewEventRouter(client.logger),
+		client:      client,
+		encryptKey:  encryptKey,
+		verifyToken: verifyToken,
+		dedupWindow: 1024,
+		maxEventAge: DefaultMaxEventAge,
+
+		maxBodySize:    DefaultMaxBodySize,
+		maxDecodedSize: DefaultMaxDecodedBodySize,
+	}
+
+	for _, opt := range opts {
+		opt(wh)
+	}
+	if client.ignoreSelf {
+		wh.AddFilter(client.ignoreSelfFilter)
+		client.User.prefetchSelfID()
 	}
-}
 
-// OnEvent 注册事件处理器
-func (wh *WebhookHandler) OnEvent(eventType int, handler EventHandler) {
-	wh.mu.Lock()
-	defer wh.mu.Unlock()
-	wh.eventHandlers[eventType] = append(wh.eventHandlers[eventType], handler)
+	if wh.dedupWindow > 0 {
+		wh.dedup = newSNDeduplicator(wh.dedupWindow)
+	}
+
+	return wh
 }
 
 // HandleRequest 处理HTTP请求
 func (wh *WebhookHandler) HandleRequest(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		wh.respond(w, HandleResult{StatusCode: http.StatusMethodNotAllowed, Err: fmt.Errorf("不支持的请求方法: %s", r.Method)})
 		return
 	}
 
+	if wh.maxBodySize > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, wh.maxBodySize)
+	}
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		wh.client.logger.WithError(err).Error("读取请求体失败")
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			wh.reject(WebhookRejectTooLarge)
+			wh.client.logger.Warnf("Webhook请求体超过 %d 字节，已拒绝 (来源 %s)", maxErr.Limit, wh.ClientIP(r))
+			wh.respond(w, HandleResult{StatusCode: http.StatusRequestEntityTooLarge, Err: ErrBodyTooLarge})
+			return
+		}
+		wh.client.logger.WithError(err).Errorf("读取请求体失败 (来源 %s)", wh.ClientIP(r))
+		wh.respond(w, HandleResult{StatusCode: http.StatusBadRequest, Err: err})
 		return
 	}
 	defer r.Body.Close()
+	raw := body
 
-	body, err = decodeRequestBody(body, r.Header.Get("Content-Encoding"))
+	body, err = wh.decodeRequestBody(body, r.Header.Get("Content-Encoding"))
 	if err != nil {
-		wh.client.logger.WithError(err).Error("解码Webhook请求体失败")
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		wh.callRawBodyHook(raw, nil)
+		var encErr *UnsupportedEncodingError
+		switch {
+		case errors.As(err, &encErr):
+			wh.reject(WebhookRejectDecode)
+			wh.client.logger.WithError(err).Errorf("Webhook请求体编码不受支持: %s (来源 %s)", encErr.Encoding, wh.ClientIP(r))
+			wh.respond(w, HandleResult{StatusCode: http.StatusUnsupportedMediaType, Err: err})
+		case errors.Is(err, ErrBodyTooLarge):
+			wh.reject(WebhookRejectTooLarge)
+			wh.client.logger.Warnf("Webhook请求体解压后超过 %d 字节，已拒绝 (来源 %s)", wh.maxDecodedSize, wh.ClientIP(r))
+			wh.respond(w, HandleResult{StatusCode: http.StatusRequestEntityTooLarge, Err: err})
+		default:
+			wh.reject(WebhookRejectDecode)
+			wh.client.logger.WithError(err).Errorf("解码Webhook请求体失败 (来源 %s)", wh.ClientIP(r))
+			wh.respond(w, HandleResult{StatusCode: http.StatusBadRequest, Err: err})
+		}
 		return
 	}
 
 	body, err = wh.tryDecryptBody(body)
 	if err != nil {
-		wh.client.logger.WithError(err).Error("解密Webhook请求体失败")
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		wh.reject(WebhookRejectDecrypt)
+		wh.callRawBodyHook(raw, nil)
+		wh.client.logger.WithError(err).Errorf("解密Webhook请求体失败 (来源 %s)", wh.ClientIP(r))
+		wh.respond(w, HandleResult{StatusCode: http.StatusBadRequest, Err: err})
 		return
 	}
+	wh.callRawBodyHook(raw, body)
 
 	wh.client.logger.Debugf("收到Webhook消息: %s", string(body))
 
 	var msg WebhookMessage
 	if err := json.Unmarshal(body, &msg); err != nil {
-		wh.client.logger.WithError(err).Error("解析Webhook消息失败")
-		http.Error(w, "Bad Request", http.StatusBadRequest)
+		wh.reject(WebhookRejectDecode)
+		wh.client.logger.WithError(err).Errorf("解析Webhook消息失败 (来源 %s)", wh.ClientIP(r))
+		wh.respond(w, HandleResult{StatusCode: http.StatusBadRequest, Err: err})
 		return
 	}
 
-	challenge, err := wh.handleMessage(&msg)
+	challenge, err := wh.handleMessage(r.Context(), &msg)
 	if err != nil {
-		wh.client.logger.WithError(err).Error("处理Webhook消息失败")
-		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		wh.client.logger.WithError(err).Errorf("处理Webhook消息失败 (来源 %s)", wh.ClientIP(r))
+		wh.respond(w, HandleResult{StatusCode: http.StatusUnauthorized, Err: err})
+		return
+	}
+
+	wh.respond(w, HandleResult{StatusCode: http.StatusOK, Challenge: challenge})
+}
+
+// respond 通过响应钩子（默认 DefaultWebhookResponse）写出处理结果
+func (wh *WebhookHandler) respond(w http.ResponseWriter, result HandleResult) {
+	if wh.responseWriter == nil {
+		DefaultWebhookResponse(w, result)
+		return
+	}
+	wh.responseWriter(w, result)
+}
+
+// HandleResult Webhook请求的处理结果，用于自定义响应
+type HandleResult struct {
+	StatusCode int    // 默认响应使用的 HTTP 状态码
+	Challenge  string // 验证挑战请求需要回传的 challenge，其他请求为空
+	Err        error  // 处理失败的原因，成功时为 nil
+}
+
+// DefaultWebhookResponse 默认的响应方式：失败时返回状态码对应的文本，
+// challenge 请求返回 {"challenge": ...}，其他成功请求返回 {"code":0}
+// 自定义响应钩子可以只处理关心的情况，其余交给该函数
+func DefaultWebhookResponse(w http.ResponseWriter, result HandleResult) {
+	if result.Err != nil {
+		http.Error(w, http.StatusText(result.StatusCode), result.StatusCode)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if challenge != "" {
-		_ = json.NewEncoder(w).Encode(map[string]string{"challenge": challenge})
+	if result.Challenge != "" {
+		_ = json.NewEncoder(w).Encode(map[string]string{"challenge": result.Challenge})
 		return
 	}
 	_, _ = w.Write([]byte(`{"code":0}`))
 }
 
-func decodeRequestBody(body []byte, encoding string) ([]byte, error) {
-	switch strings.ToLower(strings.TrimSpace(encoding)) {
-	case "", "identity":
-		return body, nil
-	case "gzip":
-		r, err := gzip.NewReader(bytes.NewReader(body))
-		if err != nil {
-			return nil, err
+// callRawBodyHook 调用原始请求体钩子并恢复其中的panic
+func (wh *WebhookHandler) callRawBodyHook(raw, decoded []byte) {
+	if wh.rawBodyHook == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			wh.client.logger.Errorf("Webhook原始请求体钩子发生panic: %v", r)
 		}
-		defer r.Close()
-		return io.ReadAll(r)
-	case "deflate":
-		r, err := zlib.NewReader(bytes.NewReader(body))
-		if err != nil {
-			return nil, err
+	}()
+	wh.rawBodyHook(raw, decoded)
+}
+
+// DefaultMaxDecodedBodySize 解压后请求体的默认大小上限
+const DefaultMaxDecodedBodySize = 8 << 20
+
+// ErrBodyTooLarge 请求体超过大小限制
+var ErrBodyTooLarge = errors.New("请求体超过大小限制")
+
+// UnsupportedEncodingError 请求体使用了不支持的 Content-Encoding
+type UnsupportedEncodingError struct {
+	Encoding string // 原始的 Content-Encoding 值
+}
+
+// Error 实现 error 接口
+func (e *UnsupportedEncodingError) Error() string {
+	return fmt.Sprintf("不支持的Content-Encoding: %s", e.Encoding)
+}
+
+// decodeRequestBody 按 Content-Encoding 解压请求体，解压结果超过上限时返回 ErrBodyTooLarge
+func (wh *WebhookHandler) decodeRequestBody(body []byte, encoding string) ([]byte, error) {
+	name := strings.ToLower(strings.TrimSpace(encoding))
+
+	var decoder ContentDecoder
+	switch {
+	case wh.decoders[name] != nil:
+		decoder = wh.decoders[name]
+	case name == "" || name == "identity":
+		// KOOK 在回调地址未设置 compress=0 时会发送 zlib 压缩的请求体，但不带 Content-Encoding
+		if !isZlibStream(body) {
+			return body, nil
 		}
-		defer r.Close()
-		return io.ReadAll(r)
+		decoder = newZlibDecoder
+	case name == "gzip":
+		decoder = newGzipDecoder
+	case name == "deflate":
+		decoder = newZlibDecoder
+	case name == "zstd":
+		decoder = wh.newZstdDecoder
 	default:
-		return nil, fmt.Errorf("不支持的Content-Encoding: %s", encoding)
+		return nil, &UnsupportedEncodingError{Encoding: encoding}
+	}
+
+	r, err := decoder(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return readAllLimited(r, wh.maxDecodedSize)
+}
+
+// newZstdDecoder 内置的 zstd 解码器，单协程流式解码，窗口大小不超过解压后的大小上限以限制内存占用
+func (wh *WebhookHandler) newZstdDecoder(r io.Reader) (io.ReadCloser, error) {
+	opts := []zstd.DOption{zstd.WithDecoderConcurrency(1)}
+	if limit := wh.maxDecodedSize; limit > 0 {
+		window := max(uint64(limit), zstd.MinWindowSize)
+		opts = append(opts, zstd.WithDecoderMaxWindow(window), zstd.WithDecoderMaxMemory(window))
+	}
+	d, err := zstd.NewReader(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
+
+// newGzipDecoder 内置的 gzip 解码器
+func newGzipDecoder(r io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(r)
+}
+
+// newZlibDecoder 内置的 deflate（zlib）解码器
+func newZlibDecoder(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+
+// readAllLimited 读取全部数据，超过 limit 字节时返回 ErrBodyTooLarge，limit <= 0 表示不限制
+func readAllLimited(r io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(r)
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrBodyTooLarge
+	}
+	return data, nil
+}
+
+// isZlibStream 根据 zlib 头（CMF/FLG）判断数据是否为 zlib 压缩流
+func isZlibStream(data []byte) bool {
+	if len(data) < 2 {
+		return false
 	}
+	cmf, flg := data[0], data[1]
+	return cmf&0x0f == 8 && (uint16(cmf)<<8|uint16(flg))%31 == 0
 }
 
 func (wh *WebhookHandler) tryDecryptBody(body []byte) ([]byte, error) {
-	var encrypted encryptedWebhookMessage
-	if err := json.Unmarshal(body, &encrypted); err != nil {
+	if wh.encryption == encryptionOff {
 		return body, nil
 	}
-	if encrypted.Encrypt == "" {
+
+	var encrypted encryptedWebhookMessage
+	if err := json.Unmarshal(body, &encrypted); err != nil || encrypted.Encrypt == "" {
+		if wh.encryption == encryptionOn {
+			return nil, fmt.Errorf("Webhook已启用加密，但收到未加密的消息")
+		}
 		return body, nil
 	}
 	return decryptWebhookPayload(encrypted.Encrypt, wh.encryptKey)
@@ -210,59 +593,163 @@ func pkcs7Unpad(data []byte, blockSize int) ([]byte, error) {
 }
 
 // handleMessage 处理Webhook消息
-func (wh *WebhookHandler) handleMessage(msg *WebhookMessage) (string, error) {
+func (wh *WebhookHandler) handleMessage(ctx context.Context, msg *WebhookMessage) (string, error) {
 	if msg.S != SignalEvent {
 		return "", nil
 	}
 
 	var meta webhookPayloadMeta
 	if err := json.Unmarshal(msg.D, &meta); err != nil {
+		wh.reject(WebhookRejectDecode)
 		return "", fmt.Errorf("解析Webhook元数据失败: %w", err)
 	}
 
-	if wh.verifyToken != "" && meta.VerifyToken != wh.verifyToken {
+	if wh.verifyToken != "" && !verifyTokenEqual(meta.VerifyToken, wh.verifyToken) {
+		wh.reject(WebhookRejectToken)
 		return "", fmt.Errorf("Webhook verify_token 不匹配")
 	}
 
 	if strings.EqualFold(meta.ChannelType, "WEBHOOK_CHALLENGE") && meta.Challenge != "" {
-		wh.client.logger.Info("收到Webhook验证挑战")
+		wh.client.logger.Infof("收到Webhook验证挑战")
 		return meta.Challenge, nil
 	}
 
-	return "", wh.handleEvent(msg)
+	if err := wh.checkEventAge(meta.MsgTimestamp); err != nil {
+		wh.reject(WebhookRejectExpired)
+		return "", err
+	}
+
+	// KOOK 重发的事件带有相同的 sn，sn 为 0 时无法判断，不做去重
+	if msg.SN != 0 && wh.dedup != nil && wh.dedup.Seen(msg.SN) {
+		wh.client.logger.Debugf("忽略重复的Webhook事件: sn=%d", msg.SN)
+		return "", nil
+	}
+
+	return "", wh.handleEvent(ctx, msg)
+}
+
+// checkEventAge 校验事件时间戳是否在允许窗口内（毫秒时间戳）
+// 部分事件不携带 msg_timestamp，此时无法判断，放行并记录调试日志
+func (wh *WebhookHandler) checkEventAge(msgTimestamp int64) error {
+	if wh.maxEventAge <= 0 {
+		return nil
+	}
+	if msgTimestamp <= 0 {
+		wh.client.logger.Debugf("Webhook事件缺少msg_timestamp，跳过时间戳校验")
+		return nil
+	}
+
+	age := time.Since(time.UnixMilli(msgTimestamp))
+	if age > wh.maxEventAge || age < -wh.maxEventAge {
+		return fmt.Errorf("Webhook事件时间戳超出允许范围: 偏差 %v，允许 %v", age.Round(time.Second), wh.maxEventAge)
+	}
+	return nil
 }
 
 // handleEvent 处理事件
-func (wh *WebhookHandler) handleEvent(msg *WebhookMessage) error {
-	var event Event
-	if err := json.Unmarshal(msg.D, &event); err != nil {
-		return fmt.Errorf("解析事件失败: %w", err)
+// 同步分发时处理器直接使用请求的上下文；异步分发时请求返回后上下文即被取消，
+// 因此只保留其中的值（如追踪信息）而不继承取消
+func (wh *WebhookHandler) handleEvent(ctx context.Context, msg *WebhookMessage) error {
+	event, err := parseEvent(msg.D)
+	if err != nil {
+		return err
 	}
 
 	wh.client.logger.Debugf("收到Webhook事件: 类型=%d, 内容=%s", event.Type, event.Content)
+	wh.client.metrics.IncEvent(event.Type)
+	run := wh.invoke
+	if !wh.syncDispatch {
+		ctx = context.WithoutCancel(ctx)
+		run = wh.spawn
+	}
+	wh.dispatch(withEventContext(ctx, event), event, run)
+	return nil
+}
 
-	wh.mu.RLock()
-	handlers := append([]EventHandler(nil), wh.eventHandlers[event.Type]...)
-	wh.mu.RUnlock()
+// snDeduplicator 记录最近处理过的 sn 的环形缓冲
+type snDeduplicator struct {
+	mu   sync.Mutex
+	ring []int
+	seen map[int]struct{}
+	next int
+}
 
-	for _, handler := range handlers {
-		go func(h EventHandler) {
-			defer func() {
-				if r := recover(); r != nil {
-					wh.client.logger.Errorf("事件处理器发生panic: %v", r)
-				}
-			}()
-			h(&event)
-		}(handler)
+func newSNDeduplicator(size int) *snDeduplicator {
+	return &snDeduplicator{
+		ring: make([]int, size),
+		seen: make(map[int]struct{}, size),
 	}
+}
 
-	return nil
+// Seen 判断 sn 是否已处理过；未处理过时记录该 sn 并淘汰最旧的记录
+func (d *snDeduplicator) Seen(sn int) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.seen[sn]; ok {
+		return true
+	}
+
+	if old := d.ring[d.next]; old != 0 {
+		delete(d.seen, old)
+	}
+	d.ring[d.next] = sn
+	d.seen[sn] = struct{}{}
+	d.next = (d.next + 1) % len(d.ring)
+	return false
 }
 
 // StartWebhookServer 启动Webhook服务器
+// 使用私有的 ServeMux，同一进程中可以运行多个 WebhookHandler；调用 Shutdown 后返回 nil
 func (wh *WebhookHandler) StartWebhookServer(addr, path string) error {
-	http.HandleFunc(path, wh.HandleRequest)
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, wh.HandleRequest)
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	wh.serverMu.Lock()
+	if wh.server != nil {
+		wh.serverMu.Unlock()
+		return fmt.Errorf("Webhook服务器已在运行")
+	}
+	wh.server = server
+	wh.serverMu.Unlock()
 
 	wh.client.logger.Infof("启动Webhook服务器: %s%s", addr, path)
-	return http.ListenAndServe(addr, nil)
+	err := server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown 优雅关闭Webhook服务器
+// 停止接收新请求，并等待在途请求及异步分发中的事件处理器执行完毕，直到 ctx 结束
+func (wh *WebhookHandler) Shutdown(ctx context.Context) error {
+	wh.serverMu.Lock()
+	server := wh.server
+	wh.server = nil
+	wh.serverMu.Unlock()
+
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			return fmt.Errorf("关闭Webhook服务器失败: %w", err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wh.waitInflight()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
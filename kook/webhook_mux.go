@@ -0,0 +1,155 @@
+package kook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// WebhookMux 多机器人Webhook分发器，同一个HTTP服务中托管多个 WebhookHandler
+// 优先按 URL path 精确匹配，此时请求体只会由对应机器人的密钥解密；
+// 没有匹配的路径时按 verify_token 在 HandleByToken 注册的处理器中查找
+type WebhookMux struct {
+	mu     sync.RWMutex
+	paths  map[string]*WebhookHandler
+	tokens []*WebhookHandler
+}
+
+// NewWebhookMux 创建Webhook分发器
+func NewWebhookMux() *WebhookMux {
+	return &WebhookMux{
+		paths: make(map[string]*WebhookHandler),
+	}
+}
+
+// Handle 按 URL path 注册处理器，path 需与请求路径完全一致
+func (m *WebhookMux) Handle(path string, wh *WebhookHandler) error {
+	if path == "" {
+		return fmt.Errorf("Webhook路径不能为空")
+	}
+	if wh == nil {
+		return fmt.Errorf("Webhook处理器不能为空")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.paths[path]; ok {
+		return fmt.Errorf("Webhook路径已注册: %s", path)
+	}
+	m.paths[path] = wh
+	return nil
+}
+
+// HandleByToken 按处理器的 verify_token 注册，用于多个机器人共用同一个回调地址
+// 加密的请求需要依次尝试各处理器的密钥，只有解密后 verify_token 匹配才会交给该处理器；
+// 能区分路径时应优先使用 Handle
+func (m *WebhookMux) HandleByToken(wh *WebhookHandler) error {
+	if wh == nil {
+		return fmt.Errorf("Webhook处理器不能为空")
+	}
+	if wh.verifyToken == "" {
+		return fmt.Errorf("按 verify_token 注册的Webhook处理器必须配置verifyToken")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, registered := range m.tokens {
+		if registered.verifyToken == wh.verifyToken {
+			return fmt.Errorf("verify_token 已注册")
+		}
+	}
+	m.tokens = append(m.tokens, wh)
+	return nil
+}
+
+// ServeHTTP 实现 http.Handler
+func (m *WebhookMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.HandleRequest(w, r)
+}
+
+// HandleRequest 把请求分发给对应机器人的 WebhookHandler，找不到时返回 404
+func (m *WebhookMux) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	m.mu.RLock()
+	wh := m.paths[r.URL.Path]
+	tokens := append([]*WebhookHandler(nil), m.tokens...)
+	m.mu.RUnlock()
+
+	if wh != nil {
+		wh.HandleRequest(w, r)
+		return
+	}
+	if len(tokens) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 原始请求体按候选处理器中最宽松的上限读取，交给匹配的处理器后仍按其自身配置校验
+	var limit int64
+	for _, candidate := range tokens {
+		if candidate.maxBodySize <= 0 {
+			limit = 0
+			break
+		}
+		if candidate.maxBodySize > limit {
+			limit = candidate.maxBodySize
+		}
+	}
+	if limit > 0 {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
+	}
+	raw, err := io.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, "Request Entity Too Large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	encoding := r.Header.Get("Content-Encoding")
+	for _, candidate := range tokens {
+		if !candidate.matchVerifyToken(raw, encoding) {
+			continue
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		r.ContentLength = int64(len(raw))
+		candidate.HandleRequest(w, r)
+		return
+	}
+
+	http.NotFound(w, r)
+}
+
+// matchVerifyToken 用本处理器的配置解码、解密请求体，判断其 verify_token 是否属于本处理器
+// 仅用于路由，失败不计入验证失败统计
+func (wh *WebhookHandler) matchVerifyToken(raw []byte, encoding string) bool {
+	body, err := wh.decodeRequestBody(raw, encoding)
+	if err != nil {
+		return false
+	}
+	body, err = wh.tryDecryptBody(body)
+	if err != nil {
+		return false
+	}
+
+	var msg WebhookMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return false
+	}
+	var meta webhookPayloadMeta
+	if err := json.Unmarshal(msg.D, &meta); err != nil {
+		return false
+	}
+	return verifyTokenEqual(meta.VerifyToken, wh.verifyToken)
+}
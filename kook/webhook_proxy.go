@@ -0,0 +1,110 @@
+package kook
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// WithTrustedProxies 设置信任的反向代理（IP 或 CIDR，如 10.0.0.0/8、::1）
+// 只有直连地址属于信任代理时才读取 X-Forwarded-For / X-Real-IP 作为请求来源，
+// 否则这两个请求头可被任意伪造；未设置时日志中的来源始终是直连地址。无效的条目会被忽略并记录警告
+func WithTrustedProxies(proxies ...string) WebhookOption {
+	return func(wh *WebhookHandler) {
+		for _, p := range proxies {
+			prefix, err := parseProxyPrefix(p)
+			if err != nil {
+				wh.client.logger.Warnf("忽略无效的信任代理地址: %s", p)
+				continue
+			}
+			wh.trustedProxies = append(wh.trustedProxies, prefix)
+		}
+	}
+}
+
+// parseProxyPrefix 解析单个 IP 或 CIDR，单个 IP 视为只包含自身的网段
+func parseProxyPrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ClientIP 返回请求的真实来源 IP
+// 直连地址是信任的代理时，从右向左遍历 X-Forwarded-For 链，跳过信任的代理，第一个不受信任的地址即为来源；
+// 链上全部是信任代理时取最左侧的地址。没有 X-Forwarded-For 时使用 X-Real-IP，均无效时返回直连地址
+func (wh *WebhookHandler) ClientIP(r *http.Request) string {
+	remote := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(remote); err == nil {
+		remote = host
+	}
+	remoteAddr, err := netip.ParseAddr(remote)
+	if err != nil || !wh.isTrustedProxy(remoteAddr) {
+		return remote
+	}
+
+	// 多个 X-Forwarded-For 头按出现顺序拼接成一条链
+	var chain []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		for _, hop := range strings.Split(header, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				chain = append(chain, hop)
+			}
+		}
+	}
+	var leftmost string
+	for i := len(chain) - 1; i >= 0; i-- {
+		addr, err := parseForwardedAddr(chain[i])
+		if err != nil {
+			// 无法解析的条目之后的地址不可信，停止遍历
+			break
+		}
+		if !wh.isTrustedProxy(addr) {
+			return addr.String()
+		}
+		leftmost = addr.String()
+	}
+	if leftmost != "" {
+		return leftmost
+	}
+
+	if addr, err := parseForwardedAddr(r.Header.Get("X-Real-IP")); err == nil {
+		return addr.String()
+	}
+	return remote
+}
+
+// isTrustedProxy 判断地址是否属于信任的代理
+func (wh *WebhookHandler) isTrustedProxy(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, prefix := range wh.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseForwardedAddr 解析代理头中的地址，兼容带端口与方括号的写法
+func parseForwardedAddr(s string) (netip.Addr, error) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	return addr.Unmap(), nil
+}
@@ -0,0 +1,35 @@
+package kook
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zstdCompress(t *testing.T, data []byte) []byte {
+	t.Helper()
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer enc.Close()
+	return enc.EncodeAll(data, nil)
+}
+
+func TestWebhookDecodeZstd(t *testing.T) {
+	wh := NewWebhookHandler(NewClient("token", WithLogger(NopLogger())), "", "")
+	body := []byte(`{"s":0,"d":{"type":255,"channel_type":"WEBHOOK_CHALLENGE","challenge":"abc"}}`)
+
+	decoded, err := wh.decodeRequestBody(zstdCompress(t, body), "zstd")
+	require.NoError(t, err)
+	assert.Equal(t, body, decoded)
+}
+
+func TestWebhookDecodeZstdTooLarge(t *testing.T) {
+	wh := NewWebhookHandler(NewClient("token", WithLogger(NopLogger())), "", "", WithMaxDecodedBodySize(1024))
+	body := bytes.Repeat([]byte("a"), 4096)
+
+	_, err := wh.decodeRequestBody(zstdCompress(t, body), "ZSTD")
+	assert.Error(t, err)
+}
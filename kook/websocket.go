@@ -5,10 +5,15 @@ import (
 	"compress/zlib"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"math"
+	"math/rand"
 	"net/http"
+	"net/url"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -17,24 +22,86 @@ import (
 // EventHandler 事件处理器函数类型
 type EventHandler func(*Event)
 
+// EventHandlerCtx 接收上下文的事件处理器
+// Webhook 传入请求的上下文（异步分发时不随请求结束而取消，但保留其中的值，如追踪信息），
+// WebSocket 传入从连接上下文派生的每事件上下文，连接关闭时取消
+type EventHandlerCtx func(ctx context.Context, event *Event)
+
+// EventHandlerErr 返回错误的事件处理器，返回的错误会被记录，不影响同一事件的其余处理器
+type EventHandlerErr func(ctx context.Context, event *Event) error
+
 // WebSocketClient WebSocket客户端
 type WebSocketClient struct {
-	client          *Client
-	conn            *websocket.Conn
-	eventHandlers   map[int][]EventHandler
-	mu              sync.RWMutex
-	ctx             context.Context
-	cancel          context.CancelFunc
-	compress        bool
-	sn              int
-	sessionID       string
-	heartbeatTicker *time.Ticker
-	gatewayURL      string
-	reconnectCount  int
-	maxReconnects   int
-	reconnectDelay  time.Duration
-	isConnected     bool
-	connMu          sync.RWMutex
+	*EventRouter
+
+	client            *Client
+	conn              *websocket.Conn
+	mu                sync.RWMutex
+	ctx               context.Context
+	cancel            context.CancelFunc
+	compress          bool
+	connCompressed    atomic.Bool // 当前连接是否协商了 zlib 压缩
+	suspended         atomic.Bool // 机器人已下线，暂停自动重连
+	autoReconnect     atomic.Bool // 断线后是否自动重连，ConnectContext 收到 hello 前为 false
+	helloMu           sync.Mutex
+	helloWaiter       chan error      // ConnectContext 等待首个 hello 的结果
+	helloConn         *websocket.Conn // helloWaiter 等待的连接，由 doConnect 绑定
+	session           sessionState
+	heartbeatMu       sync.Mutex
+	heartbeat         *heartbeatMonitor
+	heartbeatCancel   context.CancelFunc
+	heartbeatInterval time.Duration
+	heartbeatTimeout  time.Duration
+	resumeTimeout     time.Duration
+	dialer            *websocket.Dialer
+	dialHeader        http.Header
+	gatewayURL        string
+	reconnectCount    atomic.Int32
+	maxReconnects     int
+	reconnectPolicy   ReconnectPolicy
+	onReconnect       func(attempt int, delay time.Duration)
+	isConnected       bool
+	connDone          chan struct{} // 当前连接的读循环结束时关闭
+	connMu            sync.RWMutex
+	state             connectionStateMachine
+	eventBufferSize   int
+	events            *eventSequencer
+	eventWorkers      int
+	eventQueueSize    int
+	dropOnQueueFull   bool
+	workers           *eventWorkerPool
+	loops             sync.WaitGroup // 读循环、重连与心跳协程，Close 时等待全部退出
+	callbacks         atomic.Int32   // 后台协程中正在执行的用户回调数，非零时 Close 不等待后台协程
+}
+
+// sessionState 网关会话状态
+// sn 与 sessionID 会在读循环、心跳和重连之间并发访问，统一通过原子操作读写
+type sessionState struct {
+	sn        atomic.Int64
+	sessionID atomic.Pointer[string]
+}
+
+// LoadSN 读取当前已处理的最大连续 sn
+func (s *sessionState) LoadSN() int64 {
+	return s.sn.Load()
+}
+
+// StoreSN 直接设置 sn（用于新会话归零等场景）
+func (s *sessionState) StoreSN(sn int64) {
+	s.sn.Store(sn)
+}
+
+// LoadSessionID 读取当前会话ID
+func (s *sessionState) LoadSessionID() string {
+	if id := s.sessionID.Load(); id != nil {
+		return *id
+	}
+	return ""
+}
+
+// StoreSessionID 设置当前会话ID
+func (s *sessionState) StoreSessionID(sessionID string) {
+	s.sessionID.Store(&sessionID)
 }
 
 // WebSocketMessage WebSocket消息结构
@@ -77,50 +144,275 @@ const (
 	SignalResumeAck = 6 // 服务端发送，客户端接收，代表重连成功
 )
 
+// ReconnectPolicy 网关重连的退避策略
+// 第 n 次重连等待 MinDelay * Factor^(n-1)，不超过 MaxDelay；开启 Jitter 时在 [delay/2, delay] 内随机
+type ReconnectPolicy struct {
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	Factor   float64
+	Jitter   bool
+}
+
+// DefaultReconnectPolicy 返回默认重连策略
+func DefaultReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		MinDelay: 2 * time.Second,
+		MaxDelay: 2 * time.Minute,
+		Factor:   2,
+		Jitter:   true,
+	}
+}
+
+// Delay 计算第 attempt 次（从1开始）重连前的等待时间
+func (p ReconnectPolicy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	factor := p.Factor
+	if factor < 1 {
+		factor = 1
+	}
+
+	delay := float64(p.MinDelay) * math.Pow(factor, float64(attempt-1))
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		delay = float64(p.MaxDelay)
+	}
+
+	if p.Jitter && delay > 0 {
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+	return time.Duration(delay)
+}
+
+// WebSocketOption WebSocket客户端配置选项
+type WebSocketOption func(*WebSocketClient)
+
+// WithReconnectPolicy 设置重连退避策略：延迟从 min 开始按 factor 指数增长，达到 max 后保持
+func WithReconnectPolicy(min, max time.Duration, factor float64, jitter bool) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		ws.reconnectPolicy = ReconnectPolicy{
+			MinDelay: min,
+			MaxDelay: max,
+			Factor:   factor,
+			Jitter:   jitter,
+		}
+	}
+}
+
+// WithMaxReconnects 设置连续重连的最大次数
+func WithMaxReconnects(n int) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if n >= 0 {
+			ws.maxReconnects = n
+		}
+	}
+}
+
+// WithHeartbeatInterval 设置心跳（ping）间隔，默认 30 秒
+func WithHeartbeatInterval(interval time.Duration) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if interval > 0 {
+			ws.heartbeatInterval = interval
+		}
+	}
+}
+
+// WithHeartbeatTimeout 设置等待 pong 的超时时间，默认 6 秒，连续两次超时将重连
+func WithHeartbeatTimeout(timeout time.Duration) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if timeout > 0 {
+			ws.heartbeatTimeout = timeout
+		}
+	}
+}
+
+// DefaultResumeTimeout 恢复会话时等待 hello 的默认超时
+const DefaultResumeTimeout = 6 * time.Second
+
+// WithResumeTimeout 设置恢复会话时等待服务端 hello 的超时，超时后丢弃旧会话重新连接，默认 6 秒
+func WithResumeTimeout(timeout time.Duration) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if timeout > 0 {
+			ws.resumeTimeout = timeout
+		}
+	}
+}
+
+// DefaultHandshakeTimeout 网关 WebSocket 握手的默认超时
+const DefaultHandshakeTimeout = 15 * time.Second
+
+// defaultDialer 默认的网关拨号器：按环境变量（HTTPS_PROXY 等）使用代理，握手超时 15 秒
+func defaultDialer() *websocket.Dialer {
+	return &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: DefaultHandshakeTimeout,
+	}
+}
+
+// WithGatewayCompression 设置是否请求网关下发 zlib 压缩的数据，覆盖 NewWebSocketClient 的 compress 参数
+// 开启后可明显节省流量，代价是少量 CPU；解压失败时视为连接损坏，断开后重连恢复
+func WithGatewayCompression(enabled bool) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		ws.compress = enabled
+	}
+}
+
+// WithWebSocketDialer 使用自定义的拨号器连接网关，可设置代理、TLS 配置、握手超时等
+// 例如走公司代理：&websocket.Dialer{Proxy: http.ProxyURL(proxyURL), HandshakeTimeout: 30 * time.Second}
+func WithWebSocketDialer(dialer *websocket.Dialer) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if dialer != nil {
+			ws.dialer = dialer
+		}
+	}
+}
+
+// WithWebSocketHeader 设置握手请求附加的请求头，Authorization 总是由客户端设置，未设置 User-Agent 时使用客户端的 User-Agent
+func WithWebSocketHeader(header http.Header) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		ws.dialHeader = header.Clone()
+	}
+}
+
+// WithEventBufferSize 设置乱序事件缓冲的最大条数，超出后跳过缺失的 sn 并记录警告
+func WithEventBufferSize(n int) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if n > 0 {
+			ws.eventBufferSize = n
+		}
+	}
+}
+
+// WithEventWorkers 使用固定数量的 worker 执行事件处理器，替代每个处理器一个 goroutine
+// 事件投递到有界队列（默认长度 DefaultEventQueueSize），n <= 0 表示不启用
+func WithEventWorkers(n int) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		ws.eventWorkers = n
+	}
+}
+
+// WithEventQueue 设置 worker pool 的队列长度，以及队列满时是丢弃事件（true）还是阻塞读循环（false，默认）
+// 丢弃的事件数可通过 DroppedEvents 获取
+func WithEventQueue(size int, dropOnFull bool) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		ws.eventQueueSize = size
+		ws.dropOnQueueFull = dropOnFull
+	}
+}
+
+// WithGatewayRouter 使用指定的事件路由器，可与 WebhookHandler 共享同一套事件处理器
+func WithGatewayRouter(router *EventRouter) WebSocketOption {
+	return func(ws *WebSocketClient) {
+		if router != nil {
+			ws.EventRouter = router
+		}
+	}
+}
+
 // NewWebSocketClient 创建新的WebSocket客户端
-func NewWebSocketClient(client *Client, compress bool) *WebSocketClient {
+func NewWebSocketClient(client *Client, compress bool, opts ...WebSocketOption) *WebSocketClient {
+	ws := newWebSocketClient(client, compress, opts...)
+	client.trackGateway(ws)
+	return ws
+}
+
+// newWebSocketClient 创建网关连接，不登记到 Client
+func newWebSocketClient(client *Client, compress bool, opts ...WebSocketOption) *WebSocketClient {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &WebSocketClient{
-		client:         client,
-		eventHandlers:  make(map[int][]EventHandler),
-		ctx:            ctx,
-		cancel:         cancel,
-		compress:       compress,
-		maxReconnects:  10,
-		reconnectDelay: 5 * time.Second,
+	ws := &WebSocketClient{
+		EventRouter:     NewEventRouter(client.logger),
+		client:          client,
+		ctx:             ctx,
+		cancel:          cancel,
+		compress:        compress,
+		maxReconnects:   10,
+		reconnectPolicy: DefaultReconnectPolicy(),
+
+		heartbeatInterval: DefaultHeartbeatInterval,
+		heartbeatTimeout:  DefaultHeartbeatTimeout,
+		resumeTimeout:     DefaultResumeTimeout,
+		dialer:            defaultDialer(),
+		eventBufferSize:   DefaultEventBufferSize,
+	}
+
+	for _, opt := range opts {
+		opt(ws)
 	}
+	if client.ignoreSelf {
+		ws.AddFilter(client.ignoreSelfFilter)
+	}
+	ws.events = newEventSequencer(ws.eventBufferSize)
+	if ws.eventWorkers > 0 {
+		ws.workers = newEventWorkerPool(ws.eventWorkers, ws.eventQueueSize, ws.dropOnQueueFull, func(ctx context.Context, h EventHandlerCtx, event *Event) {
+			ws.runCallback(func() { ws.invoke(ctx, h, event) })
+		})
+	}
+
+	return ws
 }
 
-// OnEvent 注册事件处理器
-func (ws *WebSocketClient) OnEvent(eventType int, handler EventHandler) {
+// ConnectionState 返回当前网关连接状态
+func (ws *WebSocketClient) ConnectionState() ConnectionState {
+	return ws.state.Load()
+}
+
+// OnStateChange 注册连接状态变化回调，回调按状态变化的顺序在单独的协程中串行执行
+// 回调中可以调用 Close、Connect 等方法，但长时间阻塞会推迟后续状态的通知
+func (ws *WebSocketClient) OnStateChange(handler StateChangeHandler) {
+	ws.state.OnChange(handler)
+}
+
+// OnReconnect 设置重连回调，每次重连等待前以重连次数和等待时长调用
+func (ws *WebSocketClient) OnReconnect(fn func(attempt int, delay time.Duration)) {
 	ws.mu.Lock()
 	defer ws.mu.Unlock()
 
-	ws.eventHandlers[eventType] = append(ws.eventHandlers[eventType], handler)
+	ws.onReconnect = fn
+}
+
+// notifyReconnect 通知重连回调
+func (ws *WebSocketClient) notifyReconnect(attempt int, delay time.Duration) {
+	ws.mu.RLock()
+	fn := ws.onReconnect
+	ws.mu.RUnlock()
+
+	if fn != nil {
+		ws.runCallback(func() { fn(attempt, delay) })
+	}
 }
 
-// Connect 连接到WebSocket网关
+// Connect 连接到WebSocket网关，连接失败时按重连策略重试，连接建立后断线自动重连
+// 需要控制启动耗时的场景使用 ConnectContext
 func (ws *WebSocketClient) Connect() error {
-	return ws.connectWithRetry()
+	ws.autoReconnect.Store(true)
+	ws.state.Transition(ConnectionStateConnecting)
+	if err := ws.connectWithRetry(); err != nil {
+		ws.state.Transition(ConnectionStateClosed)
+		return err
+	}
+	return nil
 }
 
 // connectWithRetry 带重试的连接
 func (ws *WebSocketClient) connectWithRetry() error {
 	for attempts := 0; attempts <= ws.maxReconnects; attempts++ {
-		err := ws.doConnect()
+		err := ws.doConnect(ws.ctx)
 		if err == nil {
-			ws.reconnectCount = 0
+			ws.reconnectCount.Store(0)
 			return nil
 		}
 
 		ws.client.logger.WithError(err).Errorf("WebSocket连接失败，尝试 %d/%d", attempts+1, ws.maxReconnects+1)
 
 		if attempts < ws.maxReconnects {
+			delay := ws.reconnectPolicy.Delay(attempts + 1)
+			ws.notifyReconnect(attempts+1, delay)
 			select {
 			case <-ws.ctx.Done():
 				return ws.ctx.Err()
-			case <-time.After(ws.reconnectDelay * time.Duration(attempts+1)):
+			case <-time.After(delay):
 				// 指数退避
 			}
 		}
@@ -129,41 +421,158 @@ func (ws *WebSocketClient) connectWithRetry() error {
 	return fmt.Errorf("WebSocket连接失败，已达到最大重试次数")
 }
 
-// doConnect 执行实际连接
-func (ws *WebSocketClient) doConnect() error {
-	// 获取网关信息
-	compress := 0
-	if ws.compress {
-		compress = 1
+// ConnectContext 首次连接网关，获取网关地址、握手到收到 hello 的全过程受 ctx 控制
+// 与 Connect 不同，首次连接只尝试一次：失败、hello 返回错误码或 ctx 结束时关闭连接并返回错误，
+// 由调用方决定是否重试；收到 hello 建立会话后才进入断线自动重连模式
+func (ws *WebSocketClient) ConnectContext(ctx context.Context) error {
+	if ws.ctx.Err() != nil {
+		return fmt.Errorf("WebSocket客户端已关闭")
+	}
+
+	// 客户端被 Close 时同样中断首次连接
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(ws.ctx, cancel)
+	defer stop()
+
+	waiter := make(chan error, 1)
+	ws.helloMu.Lock()
+	ws.helloWaiter = waiter
+	ws.helloConn = nil
+	ws.helloMu.Unlock()
+
+	ws.autoReconnect.Store(false)
+	ws.state.Transition(ConnectionStateConnecting)
+	if err := ws.doConnect(ctx); err != nil {
+		ws.notifyHello(nil, err)
+		ws.state.Transition(ConnectionStateClosed)
+		return err
+	}
+
+	helloResult := func(err error) error {
+		if err != nil {
+			ws.state.Transition(ConnectionStateClosed)
+			return fmt.Errorf("建立网关会话失败: %w", err)
+		}
+		ws.reconnectCount.Store(0)
+		return nil
+	}
+	select {
+	case err := <-waiter:
+		return helloResult(err)
+	case <-ctx.Done():
+	}
+
+	// hello 与 ctx 结束同时到达时 select 可能选中后者，先检查会话是否已建立，避免关闭刚建立的连接
+	select {
+	case err := <-waiter:
+		return helloResult(err)
+	default:
+	}
+
+	// 超时后关闭连接，读循环退出时会把结果写入 waiter；关闭前 hello 恰好到达时同样视为成功
+	ws.connMu.Lock()
+	if ws.conn != nil {
+		ws.conn.Close()
+	}
+	ws.connMu.Unlock()
+	if err := <-waiter; err == nil {
+		return nil
+	}
+	ws.state.Transition(ConnectionStateClosed)
+	return fmt.Errorf("等待网关 hello 超时: %w", ctx.Err())
+}
+
+// bindHelloWaiter 把等待中的 ConnectContext 绑定到新建立的连接，之后只接受该连接的结果
+func (ws *WebSocketClient) bindHelloWaiter(conn *websocket.Conn) {
+	ws.helloMu.Lock()
+	defer ws.helloMu.Unlock()
+	if ws.helloWaiter != nil && ws.helloConn == nil {
+		ws.helloConn = conn
+	}
+}
+
+// notifyHello 把 conn 上首次连接的结果交给 ConnectContext，没有等待者或等待的不是该连接时忽略
+// 避免下线前旧连接的读循环退出时把错误交给重新上线的连接
+func (ws *WebSocketClient) notifyHello(conn *websocket.Conn, err error) {
+	ws.helloMu.Lock()
+	defer ws.helloMu.Unlock()
+	if ws.helloWaiter != nil && ws.helloConn == conn {
+		ws.helloWaiter <- err
+		ws.helloWaiter = nil
+		ws.helloConn = nil
 	}
+}
 
-	gateway, err := ws.client.Gateway.GetGateway(ws.ctx, compress)
+// doConnect 执行实际连接，ctx 控制获取网关地址与握手
+func (ws *WebSocketClient) doConnect(ctx context.Context) error {
+	// 获取网关信息
+	gatewayURL, err := ws.client.GetGateway(ctx, ws.compress)
 	if err != nil {
 		return fmt.Errorf("获取网关信息失败: %w", err)
 	}
 
-	ws.gatewayURL = gateway.URL
+	ws.gatewayURL = gatewayURL
+
+	// 已有会话时携带 sn 与 session_id 恢复，服务端会补发断线期间的事件
+	dialURL := gatewayURL
+	resuming := false
+	if sessionID := ws.session.LoadSessionID(); sessionID != "" {
+		u, err := url.Parse(gatewayURL)
+		if err != nil {
+			return fmt.Errorf("解析网关地址失败: %w", err)
+		}
+		q := u.Query()
+		q.Set("resume", "1")
+		q.Set("sn", strconv.FormatInt(ws.session.LoadSN(), 10))
+		q.Set("session_id", sessionID)
+		u.RawQuery = q.Encode()
+		dialURL = u.String()
+		resuming = true
+		ws.state.Transition(ConnectionStateResuming)
+	}
 
 	// 创建WebSocket连接
-	header := http.Header{}
-	header.Set("Authorization", fmt.Sprintf("%s %s", ws.client.tokenType, ws.client.token))
+	header := ws.dialHeader.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	auth, err := ws.client.authorization(ctx)
+	if err != nil {
+		return err
+	}
+	header.Set("Authorization", auth)
+	if header.Get("User-Agent") == "" {
+		header.Set("User-Agent", ws.client.userAgent)
+	}
 
-	ws.client.logger.Infof("连接到WebSocket网关: %s", gateway.URL)
+	ws.client.logger.Infof("连接到WebSocket网关: %s", gatewayURL)
 
-	conn, _, err := websocket.DefaultDialer.Dial(gateway.URL, header)
+	conn, resp, err := ws.dialer.DialContext(ctx, dialURL, header)
 	if err != nil {
+		if resp != nil {
+			return fmt.Errorf("WebSocket连接失败 (HTTP %d): %w", resp.StatusCode, err)
+		}
 		return fmt.Errorf("WebSocket连接失败: %w", err)
 	}
+	ws.connCompressed.Store(gatewayCompressed(gatewayURL, ws.compress))
 
+	done := make(chan struct{})
 	ws.connMu.Lock()
 	ws.conn = conn
+	ws.connDone = done
 	ws.isConnected = true
 	ws.connMu.Unlock()
+	ws.bindHelloWaiter(conn)
+
+	ws.client.logger.Infof("WebSocket连接成功")
 
-	ws.client.logger.Info("WebSocket连接成功")
+	if resuming {
+		ws.watchResume(conn)
+	}
 
 	// 启动消息处理协程
-	go ws.handleMessages()
+	ws.goLoop(func() { ws.handleMessages(conn, done) })
 
 	return nil
 }
@@ -171,20 +580,52 @@ func (ws *WebSocketClient) doConnect() error {
 // Close 关闭WebSocket连接
 func (ws *WebSocketClient) Close() error {
 	ws.cancel()
-
-	if ws.heartbeatTicker != nil {
-		ws.heartbeatTicker.Stop()
+	ws.state.Transition(ConnectionStateClosed)
+	ws.stopHeartbeat()
+	if ws.workers != nil {
+		ws.workers.stop()
 	}
 
+	var err error
+	ws.connMu.RLock()
 	if ws.conn != nil {
-		return ws.conn.Close()
+		err = ws.conn.Close()
 	}
+	ws.connMu.RUnlock()
+	ws.client.untrackGateway(ws)
 
-	return nil
+	// 在事件处理器、过滤器或重连回调中调用时，所在协程正是要等待的后台协程，只发出关闭信号，
+	// 各协程在回调返回后自行退出；否则等待全部退出，Close 返回后连接不再占用任何后台协程
+	if ws.callbacks.Load() > 0 {
+		return err
+	}
+	if ws.workers != nil {
+		ws.workers.wait()
+	}
+	ws.loops.Wait()
+	return err
+}
+
+// goLoop 启动一个受 Close 等待的后台协程
+func (ws *WebSocketClient) goLoop(fn func()) {
+	ws.loops.Add(1)
+	go func() {
+		defer ws.loops.Done()
+		fn()
+	}()
 }
 
-// handleMessages 处理WebSocket消息
-func (ws *WebSocketClient) handleMessages() {
+// runCallback 在后台协程中执行用户代码（事件处理器、过滤器、重连回调），供 Close 判断能否等待后台协程退出
+func (ws *WebSocketClient) runCallback(fn func()) {
+	ws.callbacks.Add(1)
+	defer ws.callbacks.Add(-1)
+	fn()
+}
+
+// handleMessages 读取并处理 conn 上的消息，退出时关闭 done
+// 连接状态、hello 结果与重连只由仍是当前连接的读循环处理，已被新连接取代的旧读循环不会影响新连接
+func (ws *WebSocketClient) handleMessages(conn *websocket.Conn, done chan struct{}) {
+	exitErr := errors.New("WebSocket连接已断开")
 	defer func() {
 		if r := recover(); r != nil {
 			ws.client.logger.Errorf("WebSocket消息处理发生panic: %v", r)
@@ -192,11 +633,24 @@ func (ws *WebSocketClient) handleMessages() {
 
 		// 标记连接已断开
 		ws.connMu.Lock()
-		ws.isConnected = false
+		current := ws.conn == conn
+		if current {
+			ws.isConnected = false
+		}
 		ws.connMu.Unlock()
 
-		// 主动关闭时不重连
-		if ws.ctx.Err() == nil {
+		reconnect := false
+		if current {
+			if !ws.autoReconnect.Load() {
+				// 首次连接尚未收到 hello 时由 ConnectContext 返回错误，不自动重连
+				ws.notifyHello(conn, exitErr)
+			} else {
+				// 主动关闭或机器人已下线时不重连
+				reconnect = ws.ctx.Err() == nil && !ws.suspended.Load()
+			}
+		}
+		close(done)
+		if reconnect {
 			ws.attemptReconnect()
 		}
 	}()
@@ -206,27 +660,22 @@ func (ws *WebSocketClient) handleMessages() {
 		case <-ws.ctx.Done():
 			return
 		default:
-			ws.connMu.RLock()
-			conn := ws.conn
-			ws.connMu.RUnlock()
-
-			if conn == nil {
-				ws.client.logger.Error("WebSocket连接为空")
-				return
-			}
-
-			_, data, err := conn.ReadMessage()
+			frameType, data, err := conn.ReadMessage()
 			if err != nil {
-				ws.client.logger.WithError(err).Error("读取WebSocket消息失败")
+				ws.client.logger.WithError(err).Errorf("读取WebSocket消息失败")
+				exitErr = fmt.Errorf("读取WebSocket消息失败: %w", err)
 				return
 			}
 
-			// 如果启用了压缩，需要解压
-			if ws.compress {
+			// 压缩连接下每个二进制帧都是一段完整的 zlib 数据；个别文本帧不压缩，原样解析
+			if ws.connCompressed.Load() && (frameType == websocket.BinaryMessage || isZlibStream(data)) {
 				data, err = ws.decompress(data)
 				if err != nil {
-					ws.client.logger.WithError(err).Error("解压消息失败")
-					continue
+					// 解压失败说明数据流已损坏，后续帧也不可信，断开后按会话恢复重连
+					ws.client.logger.WithError(err).Errorf("解压消息失败，重新连接")
+					conn.Close()
+					exitErr = fmt.Errorf("解压消息失败: %w", err)
+					return
 				}
 			}
 
@@ -234,12 +683,12 @@ func (ws *WebSocketClient) handleMessages() {
 
 			var msg WebSocketMessage
 			if err := json.Unmarshal(data, &msg); err != nil {
-				ws.client.logger.WithError(err).Error("解析WebSocket消息失败")
+				ws.client.logger.WithError(err).Errorf("解析WebSocket消息失败")
 				continue
 			}
 
-			if err := ws.handleMessage(&msg); err != nil {
-				ws.client.logger.WithError(err).Error("处理WebSocket消息失败")
+			if err := ws.handleMessage(conn, &msg); err != nil {
+				ws.client.logger.WithError(err).Errorf("处理WebSocket消息失败")
 			}
 		}
 	}
@@ -247,34 +696,119 @@ func (ws *WebSocketClient) handleMessages() {
 
 // attemptReconnect 尝试重连
 func (ws *WebSocketClient) attemptReconnect() {
-	if ws.ctx.Err() != nil {
+	if ws.ctx.Err() != nil || ws.suspended.Load() {
 		return
 	}
-	if ws.reconnectCount >= ws.maxReconnects {
-		ws.client.logger.Error("已达到最大重连次数，停止重连")
+	if int(ws.reconnectCount.Load()) >= ws.maxReconnects {
+		ws.client.logger.Errorf("已达到最大重连次数，停止重连")
+		ws.state.Transition(ConnectionStateClosed)
 		return
 	}
+	ws.state.Transition(ConnectionStateReconnecting)
 
-	ws.reconnectCount++
-	ws.client.logger.Infof("开始第 %d 次重连尝试", ws.reconnectCount)
+	attempt := int(ws.reconnectCount.Add(1))
 
-	// 等待一段时间后重连
-	delay := ws.reconnectDelay * time.Duration(ws.reconnectCount)
+	// 按退避策略等待后重连
+	delay := ws.reconnectPolicy.Delay(attempt)
+	ws.client.logger.Infof("开始第 %d 次重连尝试，等待 %v", attempt, delay)
+	ws.notifyReconnect(attempt, delay)
 	select {
 	case <-time.After(delay):
 	case <-ws.ctx.Done():
 		return
 	}
+	// 等待期间机器人可能已被下线
+	if ws.suspended.Load() {
+		return
+	}
 
-	err := ws.doConnect()
+	err := ws.doConnect(ws.ctx)
 	if err != nil {
 		ws.client.logger.WithError(err).Errorf("重连失败")
 		// 递归尝试重连
-		go ws.attemptReconnect()
+		ws.goLoop(ws.attemptReconnect)
 	} else {
-		ws.client.logger.Info("重连成功")
-		ws.reconnectCount = 0
+		ws.client.logger.Infof("重连成功")
+		ws.reconnectCount.Store(0)
+	}
+}
+
+// watchResume 恢复会话超时仍未收到 hello 时，放弃旧会话并关闭连接，由读循环触发全新重连
+func (ws *WebSocketClient) watchResume(conn *websocket.Conn) {
+	time.AfterFunc(ws.resumeTimeout, func() {
+		if ws.ctx.Err() != nil || ws.state.Load() != ConnectionStateResuming {
+			return
+		}
+		ws.connMu.RLock()
+		current := ws.conn
+		ws.connMu.RUnlock()
+		if current != conn {
+			return
+		}
+		ws.dropSession(fmt.Sprintf("恢复会话超过 %v 未响应", ws.resumeTimeout))
+	})
+}
+
+// dropSession 丢弃当前会话（sn 与 session_id）并关闭连接，读循环退出后会重新获取网关地址全新连接
+func (ws *WebSocketClient) dropSession(reason string) {
+	ws.client.logger.Warnf("丢弃网关会话，重新连接: %s", reason)
+	ws.session.StoreSN(0)
+	ws.session.StoreSessionID("")
+	ws.events.Reset()
+
+	ws.connMu.Lock()
+	ws.isConnected = false
+	if ws.conn != nil {
+		ws.conn.Close()
 	}
+	ws.connMu.Unlock()
+}
+
+// suspend 机器人下线时断开网关并暂停自动重连，避免断线重连把机器人重新拉上线
+// 下线后服务端会作废原会话，因此同时丢弃 sn 与 session_id
+// 尚未连接或已关闭的连接不受影响，返回是否确实暂停了连接
+func (ws *WebSocketClient) suspend() bool {
+	if ws.ConnectionState() == ConnectionStateClosed || !ws.suspended.CompareAndSwap(false, true) {
+		return false
+	}
+	ws.client.logger.Infof("机器人已下线，断开网关连接并暂停重连")
+	ws.stopHeartbeat()
+	ws.session.StoreSN(0)
+	ws.session.StoreSessionID("")
+	ws.events.Reset()
+
+	ws.connMu.Lock()
+	ws.isConnected = false
+	if ws.conn != nil {
+		ws.conn.Close()
+	}
+	done := ws.connDone
+	ws.connMu.Unlock()
+	ws.state.Transition(ConnectionStateClosed)
+
+	// 等待旧连接的读循环退出，避免其退出时把结果写给随后 resume 发起的新连接；
+	// 在事件过滤器等读循环中的回调里下线时无法等待自身，由 handleMessages 按连接区分
+	if done != nil && ws.callbacks.Load() == 0 {
+		<-done
+	}
+	return true
+}
+
+// resume 解除下线状态并重新连接网关，连接受 ctx 控制且只尝试一次，未处于下线状态时不做任何事
+// 连接失败时保持下线状态，之后可再次调用 SetOnline 重试
+func (ws *WebSocketClient) resume(ctx context.Context) error {
+	if !ws.suspended.CompareAndSwap(true, false) {
+		return nil
+	}
+	if ws.ctx.Err() != nil {
+		return ws.ctx.Err()
+	}
+	ws.reconnectCount.Store(0)
+	if err := ws.ConnectContext(ctx); err != nil {
+		ws.suspended.Store(true)
+		return err
+	}
+	return nil
 }
 
 // IsConnected 检查连接状态
@@ -285,14 +819,14 @@ func (ws *WebSocketClient) IsConnected() bool {
 }
 
 // handleMessage 处理单个WebSocket消息
-func (ws *WebSocketClient) handleMessage(msg *WebSocketMessage) error {
+func (ws *WebSocketClient) handleMessage(conn *websocket.Conn, msg *WebSocketMessage) error {
 	switch msg.S {
 	case SignalEvent:
 		// 处理事件
 		return ws.handleEvent(msg)
 	case SignalHello:
 		// 处理Hello消息
-		return ws.handleHello(msg)
+		return ws.handleHello(conn, msg)
 	case SignalPing:
 		// 处理Ping消息
 		return ws.handlePing(msg)
@@ -307,13 +841,18 @@ func (ws *WebSocketClient) handleMessage(msg *WebSocketMessage) error {
 		var pong PongMessage
 		if msg.D != nil {
 			if err := json.Unmarshal(msg.D, &pong); err != nil {
-				ws.client.logger.WithError(err).Debug("解析Pong消息失败，可能是空的Pong")
+				ws.client.logger.WithError(err).Debugf("解析Pong消息失败，可能是空的Pong")
 			} else {
 				ws.client.logger.Debugf("收到Pong响应，SN: %d", pong.SN)
 			}
 		} else {
-			ws.client.logger.Debug("收到Pong响应")
+			ws.client.logger.Debugf("收到Pong响应")
+		}
+		ws.heartbeatMu.Lock()
+		if ws.heartbeat != nil {
+			ws.heartbeat.Pong()
 		}
+		ws.heartbeatMu.Unlock()
 		return nil
 	default:
 		ws.client.logger.Warnf("收到未知信令类型: %d", msg.S)
@@ -324,50 +863,96 @@ func (ws *WebSocketClient) handleMessage(msg *WebSocketMessage) error {
 
 // handleEvent 处理事件消息
 func (ws *WebSocketClient) handleEvent(msg *WebSocketMessage) error {
-	var event Event
-	if err := json.Unmarshal(msg.D, &event); err != nil {
-		return fmt.Errorf("解析事件失败: %w", err)
+	event, err := parseEvent(msg.D)
+	if err != nil {
+		return err
+	}
+
+	ready, duplicate, overflow := ws.events.Push(&ws.session, int64(msg.SN), event)
+	if duplicate {
+		ws.client.logger.Debugf("忽略重复事件: sn=%d, 当前sn=%d", msg.SN, ws.session.LoadSN())
+		return nil
+	}
+	if overflow {
+		ws.client.logger.Warnf("乱序事件缓冲超过 %d 条，跳过缺失的事件，当前sn=%d", ws.events.max, ws.session.LoadSN())
+	}
+	if len(ready) == 0 {
+		ws.client.logger.Debugf("缓冲乱序事件: sn=%d, 当前sn=%d", msg.SN, ws.session.LoadSN())
+		return nil
 	}
 
-	ws.sn = msg.SN
+	for _, e := range ready {
+		ws.dispatchEvent(e)
+	}
+	return nil
+}
+
+// dispatchEvent 调用事件处理器
+func (ws *WebSocketClient) dispatchEvent(event *Event) {
 	ws.client.logger.Debugf("收到事件: 类型=%d, 内容=%s", event.Type, event.Content)
+	ws.client.metrics.IncEvent(event.Type)
 
-	// 调用事件处理器
-	ws.mu.RLock()
-	handlers := ws.eventHandlers[event.Type]
-	ws.mu.RUnlock()
+	ctx := withEventContext(ws.ctx, event)
+	run := ws.spawn
+	if ws.workers != nil {
+		run = ws.submit
+	}
+	// 过滤器在读循环中执行
+	ws.runCallback(func() { ws.dispatch(ctx, event, run) })
+}
 
-	for _, handler := range handlers {
-		go func(h EventHandler) {
-			defer func() {
-				if r := recover(); r != nil {
-					ws.client.logger.Errorf("事件处理器发生panic: %v", r)
-				}
-			}()
-			h(&event)
-		}(handler)
+// submit 把处理器投递到 worker pool，未被接收时按原因记录
+func (ws *WebSocketClient) submit(ctx context.Context, h EventHandlerCtx, event *Event) {
+	switch ws.workers.submit(ws.ctx, eventJob{ctx: ctx, handler: h, event: event}) {
+	case submitQueueFull:
+		ws.client.logger.Warnf("事件队列已满，丢弃事件: 类型=%d, 累计丢弃=%d", event.Type, ws.workers.dropped.Load())
+	case submitCanceled, submitClosed:
+		ws.client.logger.Debugf("连接已关闭，丢弃事件: 类型=%d", event.Type)
 	}
+}
 
-	return nil
+// DroppedEvents 返回因 worker pool 队列已满或连接关闭而丢弃的事件处理任务数
+func (ws *WebSocketClient) DroppedEvents() uint64 {
+	if ws.workers == nil {
+		return 0
+	}
+	return ws.workers.dropped.Load()
 }
 
 // handleHello 处理Hello消息
-func (ws *WebSocketClient) handleHello(msg *WebSocketMessage) error {
+func (ws *WebSocketClient) handleHello(conn *websocket.Conn, msg *WebSocketMessage) error {
 	var hello HelloMessage
 	if err := json.Unmarshal(msg.D, &hello); err != nil {
 		return fmt.Errorf("解析Hello消息失败: %w", err)
 	}
 
-	ws.sessionID = hello.SessionID
-	ws.client.logger.Infof("WebSocket会话建立成功: %s", ws.sessionID)
+	// 恢复失败（缺少参数、会话过期、sn 无效等）时服务端返回非零 code，只能丢弃会话重新连接
+	if hello.Code != 0 {
+		ws.notifyHello(conn, fmt.Errorf("hello 返回错误码 %d", hello.Code))
+		ws.dropSession(fmt.Sprintf("hello 返回错误码 %d", hello.Code))
+		return nil
+	}
 
-	if ws.heartbeatTicker != nil {
-		ws.heartbeatTicker.Stop()
+	// 新会话的 sn 从头计数，恢复的旧会话则沿用已处理的 sn
+	if hello.SessionID != ws.session.LoadSessionID() {
+		ws.session.StoreSN(0)
+		ws.events.Reset()
+	}
+	ws.session.StoreSessionID(hello.SessionID)
+	ws.client.logger.Infof("WebSocket会话建立成功: %s", hello.SessionID)
+	ws.state.Transition(ConnectionStateConnected)
+	if ws.client.ignoreSelf {
+		// 提前缓存自身ID，过滤器只读缓存，不在读循环中请求接口
+		ws.client.User.prefetchSelfID()
 	}
 
 	// 启动心跳
 	ws.startHeartbeat()
 
+	// 会话已建立，此后断线都自动重连
+	ws.autoReconnect.Store(true)
+	ws.notifyHello(conn, nil)
+
 	return nil
 }
 
@@ -391,80 +976,62 @@ func (ws *WebSocketClient) handlePing(msg *WebSocketMessage) error {
 }
 
 // handleReconnect 处理重连消息
+// 按协议收到 reconnect 信令后旧会话已失效，需要清空 sn 与 session_id，重新获取网关地址全新连接
 func (ws *WebSocketClient) handleReconnect(msg *WebSocketMessage) error {
-	ws.client.logger.Warn("服务器要求重连")
-
-	// 发送Resume消息
-	resume := WebSocketMessage{
-		S: SignalResume,
-	}
-
-	resumeData, _ := json.Marshal(ResumeMessage{
-		SessionID: ws.sessionID,
-		SN:        ws.sn,
-	})
-	resume.D = resumeData
-
-	return ws.sendMessage(&resume)
+	ws.dropSession("服务器要求重连")
+	return nil
 }
 
 // handleResumeAck 处理重连确认消息
 func (ws *WebSocketClient) handleResumeAck(msg *WebSocketMessage) error {
-	ws.client.logger.Info("重连成功")
+	ws.client.logger.Infof("重连成功")
+	ws.state.Transition(ConnectionStateConnected)
 	return nil
 }
 
-// startHeartbeat 启动心跳
+// startHeartbeat 启动心跳，已有的心跳会先被停止
 func (ws *WebSocketClient) startHeartbeat() {
-	// 每30秒发送一次心跳
-	ws.heartbeatTicker = time.NewTicker(30 * time.Second)
+	ping := func() error {
+		pingData, _ := json.Marshal(PingMessage{SN: int(ws.session.LoadSN())})
+		return ws.sendMessage(&WebSocketMessage{S: SignalPing, D: pingData})
+	}
 
-	go func() {
+	monitor := newHeartbeatMonitor(ws.heartbeatInterval, ws.heartbeatTimeout, ping, ws.handleHeartbeatDead, ws.client.logger)
+	ctx, cancel := context.WithCancel(ws.ctx)
+
+	ws.heartbeatMu.Lock()
+	if ws.heartbeatCancel != nil {
+		ws.heartbeatCancel()
+	}
+	ws.heartbeat = monitor
+	ws.heartbeatCancel = cancel
+	ws.heartbeatMu.Unlock()
+
+	ws.goLoop(func() {
 		defer func() {
 			if r := recover(); r != nil {
 				ws.client.logger.Errorf("心跳处理发生panic: %v", r)
 			}
 		}()
+		monitor.run(ctx)
+	})
+}
 
-		consecutiveFailures := 0
-		const maxFailures = 3
+// stopHeartbeat 停止心跳
+func (ws *WebSocketClient) stopHeartbeat() {
+	ws.heartbeatMu.Lock()
+	defer ws.heartbeatMu.Unlock()
 
-		for {
-			select {
-			case <-ws.ctx.Done():
-				return
-			case <-ws.heartbeatTicker.C:
-				ping := WebSocketMessage{
-					S: SignalPing,
-				}
+	if ws.heartbeatCancel != nil {
+		ws.heartbeatCancel()
+		ws.heartbeatCancel = nil
+	}
+	ws.heartbeat = nil
+}
 
-				pingData, _ := json.Marshal(PingMessage{SN: ws.sn})
-				ping.D = pingData
-
-				if err := ws.sendMessage(&ping); err != nil {
-					consecutiveFailures++
-					ws.client.logger.WithError(err).Errorf("发送心跳失败 (%d/%d)", consecutiveFailures, maxFailures)
-
-					if consecutiveFailures >= maxFailures {
-						ws.client.logger.Error("连续心跳失败，触发重连")
-						ws.connMu.Lock()
-						ws.isConnected = false
-						if ws.conn != nil {
-							ws.conn.Close()
-						}
-						ws.connMu.Unlock()
-						go ws.attemptReconnect()
-						return
-					}
-				} else {
-					if consecutiveFailures > 0 {
-						ws.client.logger.Info("心跳恢复正常")
-					}
-					consecutiveFailures = 0
-				}
-			}
-		}
-	}()
+// handleHeartbeatDead 心跳连续超时：重置会话并关闭连接，由读循环触发重连
+func (ws *WebSocketClient) handleHeartbeatDead() {
+	ws.dropSession("连续心跳超时")
 }
 
 // sendMessage 发送WebSocket消息
@@ -486,6 +1053,9 @@ func (ws *WebSocketClient) sendMessage(msg *WebSocketMessage) error {
 	return conn.WriteMessage(websocket.TextMessage, data)
 }
 
+// maxGatewayFrameSize 单个网关帧解压后的大小上限
+const maxGatewayFrameSize = 16 << 20
+
 // decompress 解压数据
 func (ws *WebSocketClient) decompress(data []byte) ([]byte, error) {
 	r, err := zlib.NewReader(bytes.NewReader(data))
@@ -494,5 +1064,20 @@ func (ws *WebSocketClient) decompress(data []byte) ([]byte, error) {
 	}
 	defer r.Close()
 
-	return io.ReadAll(r)
+	return readAllLimited(r, maxGatewayFrameSize)
+}
+
+// gatewayCompressed 根据网关地址中的 compress 参数判断连接是否压缩，地址未携带该参数时以请求时的设置为准
+func gatewayCompressed(gatewayURL string, requested bool) bool {
+	u, err := url.Parse(gatewayURL)
+	if err != nil {
+		return requested
+	}
+	switch u.Query().Get("compress") {
+	case "0":
+		return false
+	case "1":
+		return true
+	}
+	return requested
 }
@@ -0,0 +1,413 @@
+package kook
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// testGateway 模拟 KOOK 网关：gateway/index 返回自身的 ws 地址，每个 ws 连接按建立顺序（从 1 开始）交给 serve 处理
+// 其余 API 请求一律返回成功
+type testGateway struct {
+	server *httptest.Server
+	dials  chan url.Values // 每次建立 ws 连接时的查询参数
+}
+
+func newTestGateway(t *testing.T, serve func(n int, conn *websocket.Conn, query url.Values)) *testGateway {
+	t.Helper()
+	g := &testGateway{dials: make(chan url.Values, 16)}
+	var upgrader websocket.Upgrader
+	var mu sync.Mutex
+	n := 0
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/gateway/index", func(w http.ResponseWriter, r *http.Request) {
+		wsURL := "ws" + strings.TrimPrefix(g.server.URL, "http") + "/gateway?compress=0"
+		data, _ := json.Marshal(map[string]interface{}{"code": 0, "data": map[string]string{"url": wsURL}})
+		w.Write(data)
+	})
+	mux.HandleFunc("/api/v3/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"message":"","data":{}}`))
+	})
+	mux.HandleFunc("/gateway", func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		mu.Lock()
+		n++
+		current := n
+		mu.Unlock()
+		g.dials <- r.URL.Query()
+		serve(current, conn, r.URL.Query())
+	})
+	g.server = httptest.NewServer(mux)
+	t.Cleanup(g.server.Close)
+	return g
+}
+
+// client 创建指向测试网关的客户端
+func (g *testGateway) client() *Client {
+	return NewClient("token", WithBaseURL(g.server.URL+"/api"), WithLogger(NopLogger()), WithoutRateLimit())
+}
+
+// nextDial 等待下一次 ws 连接，返回其查询参数
+func (g *testGateway) nextDial(t *testing.T) url.Values {
+	t.Helper()
+	select {
+	case q := <-g.dials:
+		return q
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待网关连接超时")
+		return nil
+	}
+}
+
+// writeSignal 向客户端发送一条网关信令
+func writeSignal(conn *websocket.Conn, signal int, data interface{}) error {
+	d, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return conn.WriteJSON(WebSocketMessage{S: signal, D: d})
+}
+
+// TestSessionStateConcurrentAccess 模拟读循环、心跳与重连同时读写 sn 与 sessionID，需在 -race 下运行
+func TestSessionStateConcurrentAccess(t *testing.T) {
+	var s sessionState
+	const writers, readers, rounds = 8, 8, 1000
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 1; i <= rounds; i++ {
+				s.StoreSN(int64(i))
+				if i%100 == 0 {
+					s.StoreSessionID("session-" + strconv.Itoa(w))
+				}
+			}
+		}(w)
+	}
+	for r := 0; r < readers; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < rounds; i++ {
+				sn := s.LoadSN()
+				assert.GreaterOrEqual(t, sn, int64(0))
+				assert.LessOrEqual(t, sn, int64(rounds))
+				_ = s.LoadSessionID()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(rounds), s.LoadSN(), "所有写入者最后都写入 rounds")
+	assert.Contains(t, s.LoadSessionID(), "session-")
+}
+
+// TestHeartbeatPongLossReconnects 网关不回 pong 时连续心跳超时，丢弃会话后全新重连
+func TestHeartbeatPongLossReconnects(t *testing.T) {
+	gateway := newTestGateway(t, func(n int, conn *websocket.Conn, _ url.Values) {
+		if err := writeSignal(conn, SignalHello, HelloMessage{SessionID: "session-" + strconv.Itoa(n)}); err != nil {
+			return
+		}
+		for {
+			var msg WebSocketMessage
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			// 只有第二个连接回复 pong
+			if msg.S == SignalPing && n > 1 {
+				writeSignal(conn, SignalPong, PongMessage{})
+			}
+		}
+	})
+
+	ws := NewWebSocketClient(gateway.client(), false,
+		WithHeartbeatInterval(20*time.Millisecond),
+		WithHeartbeatTimeout(20*time.Millisecond),
+		WithReconnectPolicy(time.Millisecond, time.Millisecond, 1, false))
+	defer ws.Close()
+
+	require.NoError(t, ws.ConnectContext(context.Background()))
+	gateway.nextDial(t)
+
+	// 心跳失效会丢弃会话，重连时不再携带 resume 参数
+	query := gateway.nextDial(t)
+	assert.Empty(t, query.Get("resume"))
+	assert.Eventually(t, func() bool {
+		return ws.session.LoadSessionID() == "session-2" && ws.IsConnected()
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestWorkerDispatchAppliesFilters worker pool 模式下过滤器同样生效
+func TestWorkerDispatchAppliesFilters(t *testing.T) {
+	ws := newWebSocketClient(NewClient("token", WithLogger(NopLogger())), false, WithEventWorkers(2))
+	ws.AddFilter(func(event *Event) bool { return event.AuthorID != "bot" })
+
+	handled := make(chan string, 2)
+	ws.OnEvent(EventTypeTextMessage, func(event *Event) { handled <- event.AuthorID })
+
+	ws.dispatchEvent(&Event{Type: EventTypeTextMessage, AuthorID: "bot"})
+	ws.dispatchEvent(&Event{Type: EventTypeTextMessage, AuthorID: "user"})
+
+	select {
+	case author := <-handled:
+		assert.Equal(t, "user", author)
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待事件处理超时")
+	}
+	require.NoError(t, ws.Close())
+	assert.Empty(t, handled, "被过滤的事件不应分发")
+}
+
+// TestRejectedResumeStartsFreshSession 服务端拒绝恢复会话（hello 返回非零 code）时丢弃会话，全新连接
+func TestRejectedResumeStartsFreshSession(t *testing.T) {
+	gateway := newTestGateway(t, func(n int, conn *websocket.Conn, query url.Values) {
+		hello := HelloMessage{SessionID: "session-" + strconv.Itoa(n)}
+		if query.Get("resume") == "1" {
+			hello = HelloMessage{Code: 40106}
+		}
+		if err := writeSignal(conn, SignalHello, hello); err != nil {
+			return
+		}
+		if n == 1 {
+			// 断开首个连接，触发恢复会话
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+
+	ws := NewWebSocketClient(gateway.client(), false, WithReconnectPolicy(time.Millisecond, time.Millisecond, 1, false))
+	defer ws.Close()
+
+	require.NoError(t, ws.ConnectContext(context.Background()))
+	gateway.nextDial(t)
+
+	resume := gateway.nextDial(t)
+	assert.Equal(t, "1", resume.Get("resume"))
+	assert.Equal(t, "session-1", resume.Get("session_id"))
+
+	fresh := gateway.nextDial(t)
+	assert.Empty(t, fresh.Get("resume"), "恢复被拒绝后不再携带旧会话")
+	assert.Eventually(t, func() bool {
+		return ws.session.LoadSessionID() == "session-3" && ws.ConnectionState() == ConnectionStateConnected
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestGatewayCompressionDefault Client.Connect 与 Client.WebSocket 默认请求压缩数据，WithGatewayCompression(false) 可关闭
+func TestGatewayCompressionDefault(t *testing.T) {
+	requested := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested <- r.URL.Query().Get("compress")
+		w.Write([]byte(`{"code":40000,"message":"unavailable","data":{}}`))
+	}))
+	defer server.Close()
+
+	client := NewClient("token", WithBaseURL(server.URL+"/api"), WithLogger(NopLogger()), WithoutRateLimit(), WithRetry(0, 0))
+	defer client.Close()
+	assert.True(t, client.WebSocket().compress)
+
+	_, err := client.Connect(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "1", <-requested)
+
+	_, err = client.Connect(context.Background(), WithGatewayCompression(false))
+	require.Error(t, err)
+	assert.Equal(t, "0", <-requested)
+}
+
+// TestSetOfflineSuspendsAllGateways SetOffline 暂停所有网关连接，SetOnline 在 ctx 控制下重新连接全部网关
+func TestSetOfflineSuspendsAllGateways(t *testing.T) {
+	gateway := newTestGateway(t, func(n int, conn *websocket.Conn, query url.Values) {
+		if err := writeSignal(conn, SignalHello, HelloMessage{SessionID: "session-" + strconv.Itoa(n)}); err != nil {
+			return
+		}
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	})
+	client := gateway.client()
+	defer client.Close()
+
+	ws1, err := client.Connect(context.Background())
+	require.NoError(t, err)
+	ws2, err := client.Connect(context.Background())
+	require.NoError(t, err)
+	gateway.nextDial(t)
+	gateway.nextDial(t)
+
+	require.NoError(t, client.User.SetOffline(context.Background()))
+	for _, ws := range []*WebSocketClient{ws1, ws2} {
+		assert.True(t, ws.suspended.Load())
+		assert.Equal(t, ConnectionStateClosed, ws.ConnectionState())
+	}
+
+	// ctx 已结束时立即返回，网关保持下线，之后可以重试
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.Error(t, client.User.SetOnline(ctx))
+	assert.True(t, ws1.suspended.Load())
+	assert.True(t, ws2.suspended.Load())
+
+	require.NoError(t, client.User.SetOnline(context.Background()))
+	for _, ws := range []*WebSocketClient{ws1, ws2} {
+		assert.False(t, ws.suspended.Load())
+		assert.Equal(t, ConnectionStateConnected, ws.ConnectionState())
+	}
+}
+
+// serveEventsUntilClosed 发送 hello 与一条文字消息事件，然后保持连接直到客户端断开
+func serveEventsUntilClosed(n int, conn *websocket.Conn, query url.Values) {
+	if err := writeSignal(conn, SignalHello, HelloMessage{SessionID: "session-" + strconv.Itoa(n)}); err != nil {
+		return
+	}
+	event, _ := json.Marshal(Event{ChannelType: "GROUP", Type: EventTypeTextMessage, TargetID: "channel", AuthorID: "user", MsgID: "msg"})
+	if err := conn.WriteJSON(WebSocketMessage{S: SignalEvent, D: event, SN: 1}); err != nil {
+		return
+	}
+	for {
+		if _, _, err := conn.ReadMessage(); err != nil {
+			return
+		}
+	}
+}
+
+// TestCloseFromHandlerDoesNotDeadlock 同步分发与 worker pool 模式下，在事件处理器中关闭客户端都不会等待自身而死锁
+func TestCloseFromHandlerDoesNotDeadlock(t *testing.T) {
+	for name, opts := range map[string][]WebSocketOption{
+		"sync":    nil,
+		"workers": {WithEventWorkers(2)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			gateway := newTestGateway(t, serveEventsUntilClosed)
+			client := gateway.client()
+
+			closed := make(chan error, 1)
+			router := NewEventRouter(NopLogger())
+			router.OnEvent(EventTypeTextMessage, func(event *Event) { closed <- client.Close() })
+			_, err := client.Connect(context.Background(), append(opts, WithGatewayRouter(router))...)
+			require.NoError(t, err)
+
+			select {
+			case err := <-closed:
+				assert.NoError(t, err)
+			case <-time.After(5 * time.Second):
+				t.Fatal("在处理器中调用 Close 死锁")
+			}
+		})
+	}
+}
+
+// TestCloseLeavesNoGoroutines Close 返回后读循环、心跳、重连与 worker 协程全部退出
+func TestCloseLeavesNoGoroutines(t *testing.T) {
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent())
+
+	gateway := newTestGateway(t, serveEventsUntilClosed)
+	client := gateway.client()
+
+	handled := make(chan struct{}, 1)
+	router := NewEventRouter(NopLogger())
+	router.OnEvent(EventTypeTextMessage, func(event *Event) { handled <- struct{}{} })
+	_, err := client.Connect(context.Background(), WithGatewayRouter(router), WithEventWorkers(4))
+	require.NoError(t, err)
+	require.NoError(t, client.Start(context.Background()))
+
+	select {
+	case <-handled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("等待事件处理超时")
+	}
+	require.NoError(t, client.Close())
+
+	// 测试服务器与 HTTP 连接池不属于客户端的后台协程，关闭后再检查
+	client.httpClient.CloseIdleConnections()
+	gateway.server.Close()
+}
+
+// TestCloseFromStateChangeHandler 在状态回调中关闭连接不会死锁
+func TestCloseFromStateChangeHandler(t *testing.T) {
+	gateway := newTestGateway(t, serveEventsUntilClosed)
+	ws := NewWebSocketClient(gateway.client(), false)
+
+	closed := make(chan error, 1)
+	ws.OnStateChange(func(old, new ConnectionState) {
+		if new == ConnectionStateConnected {
+			closed <- ws.Close()
+		}
+	})
+	// 回调异步执行，可能在 ConnectContext 返回前就关闭了连接，因此不检查其返回值
+	_ = ws.ConnectContext(context.Background())
+
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("在状态回调中调用 Close 死锁")
+	}
+	assert.Equal(t, ConnectionStateClosed, ws.ConnectionState())
+}
+
+// TestCloseFromReconnectCallback 在重连回调（运行在重连协程中）里关闭连接不会等待自身而死锁
+func TestCloseFromReconnectCallback(t *testing.T) {
+	gateway := newTestGateway(t, func(n int, conn *websocket.Conn, query url.Values) {
+		// 发送 hello 后立即断开，触发重连
+		writeSignal(conn, SignalHello, HelloMessage{SessionID: "session-" + strconv.Itoa(n)})
+	})
+	ws := NewWebSocketClient(gateway.client(), false, WithReconnectPolicy(time.Millisecond, time.Millisecond, 1, false))
+
+	closed := make(chan error, 1)
+	ws.OnReconnect(func(attempt int, delay time.Duration) { closed <- ws.Close() })
+	_ = ws.ConnectContext(context.Background())
+
+	select {
+	case err := <-closed:
+		// 读循环退出时已关闭旧连接，再次关闭返回 net.ErrClosed
+		if err != nil {
+			assert.ErrorIs(t, err, net.ErrClosed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("在重连回调中调用 Close 死锁")
+	}
+}
+
+// TestOfflineOnlineCyclesKeepNewConnection 反复下线、上线时，旧连接读循环的退出不影响重新上线的连接
+func TestOfflineOnlineCyclesKeepNewConnection(t *testing.T) {
+	gateway := newTestGateway(t, serveEventsUntilClosed)
+	client := gateway.client()
+	defer client.Close()
+
+	ws, err := client.Connect(context.Background())
+	require.NoError(t, err)
+	// 测试网关最多缓冲 16 次连接记录
+	for i := 0; i < 10; i++ {
+		require.NoError(t, client.User.SetOffline(context.Background()))
+		assert.False(t, ws.IsConnected())
+		require.NoError(t, client.User.SetOnline(context.Background()), "第 %d 次上线", i+1)
+		assert.True(t, ws.IsConnected())
+		assert.Equal(t, ConnectionStateConnected, ws.ConnectionState())
+	}
+}